@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/spf13/cobra"
+	"gotver/internal/analyzer"
 	"gotver/internal/constants"
+	"gotver/internal/exceptions"
 	"gotver/internal/gitops"
+	"gotver/internal/lifecycle"
 	"gotver/internal/version"
-	"log"
+	"log/slog"
 	"strings"
 )
 
@@ -20,7 +23,13 @@ var (
 	gitFlag    string
 	amend      bool
 
-	verbose bool
+	segmentFlag string
+
+	firstParentFlag bool
+	mergesOnlyFlag  bool
+
+	componentFlag  string
+	allChangedFlag bool
 )
 
 const (
@@ -29,53 +38,110 @@ const (
 )
 
 const (
-	PriorityNone = iota
-	PriorityFix
-	PriorityFeat
-	PriorityBreakingChange
+	PriorityNone           = analyzer.PriorityNone
+	PriorityFix            = analyzer.PriorityFix
+	PriorityFeat           = analyzer.PriorityFeat
+	PriorityBreakingChange = analyzer.PriorityBreakingChange
 )
 
 const (
-	message0001 = "Please provide a valid flag: --auto, --commit, --major, --minor, or --patch"
-	message0002 = "Version bumped: %v -> %v"
+	message0001 = "Please provide a valid flag: --auto, --commit, --major, --minor, --patch, or --segment"
+	message0005 = "Please provide either --component with --auto, --major, --minor, or --patch, or --all-changed alone"
+	message0002 = "Version bumped"
 )
 
 // bumpCmd represents the bump command
 var bumpCmd = &cobra.Command{
 	Use:   "bump",
 	Short: "Bump the version of the project",
-	Long:  "Bump the version of the project based on the provided flags: --auto, --commit, --major, --minor, or --patch.",
-	Run: func(cmd *cobra.Command, args []string) {
-		if !validateMode(majorFlag, minorFlag, patchFlag, autoFlag, commitFlag) {
-			log.Fatalf(message0001)
+	Long: `Bump the version of the project based on the provided flags: --auto, --commit, --major, --minor, or --patch.
+
+Exit codes:
+  0   the version was bumped
+  1   unexpected failure, e.g. a lifecycle hook failed
+  2   invalid flags or arguments, or the bump is not allowed
+  3   the configuration is invalid
+  4   a git operation failed
+  5   the git repository is not clean
+  6   pushing to the remote failed
+  10  no new version is required (see --no-change-exit-code)
+
+Run "gitver explain <code>" for the error codes printed in messages.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if componentFlag != "" || allChangedFlag {
+			if !validateComponentMode() {
+				return usageError(message0005)
+			}
+			return executeComponentMode()
+		}
+
+		if !validateMode(majorFlag, minorFlag, patchFlag, autoFlag, commitFlag, segmentFlag != "") {
+			return usageError(message0001)
+		}
+
+		if err := loadConfig(); err != nil {
+			return err
+		}
+
+		if err := applyBranchPolicy(); err != nil {
+			return err
 		}
 
-		loadConfig()
+		applyCommitOptions()
 
 		if gitFlag == CommitTag || gitFlag == CommitTagPush || autoFlag {
 			err := prepareGitOperation()
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 
 		}
 
+		tx, err := newBumpTransaction()
+		if err != nil {
+			return err
+		}
+
+		version.SetDeferWrite(true)
+
 		switch {
 		case majorFlag:
-			executeMajorMode()
+			err = executeMajorMode()
 		case minorFlag:
-			executeMinorMode()
+			err = executeMinorMode()
 		case patchFlag:
-			executePatchMode()
+			err = executePatchMode()
+		case segmentFlag != "":
+			err = executeSegmentMode()
 		case autoFlag:
-			executeAutoMode()
+			err = executeAutoMode()
 		case commitFlag:
-			executeCommitMode()
+			err = executeCommitMode()
+		}
+		if err != nil {
+			return err
 		}
 
-		executeGitOperations()
+		tx.setVersions(version.GetLastVersion(), version.ToString())
+		if err := tx.run(lifecycle.PreBump); err != nil {
+			return err
+		}
+
+		tx.snapshot(version.GetFiles()...)
+		if err := version.WriteVersion(); err != nil {
+			return tx.fail(err)
+		}
 
-		log.Printf(message0002, version.GetLastVersion(), version.ToString())
+		if err := tx.run(lifecycle.PostVersionWrite); err != nil {
+			return tx.fail(err)
+		}
+
+		if err := executeGitOperations(tx); err != nil {
+			return err
+		}
+
+		slog.Info(message0002, "from", version.GetLastVersion(), "to", version.ToString())
+		return nil
 	},
 }
 
@@ -86,11 +152,20 @@ func init() {
 	bumpCmd.Flags().BoolVar(&majorFlag, "major", false, "Bump the major version")
 	bumpCmd.Flags().BoolVar(&minorFlag, "minor", false, "Bump the minor version")
 	bumpCmd.Flags().BoolVar(&patchFlag, "patch", false, "Bump the patch version")
-	bumpCmd.Flags().BoolVar(&verbose, "verbose", false, "Bump the patch version")
 	bumpCmd.Flags().BoolVar(&amend, "amend", false, "Bump the patch version")
+	bumpCmd.Flags().StringVar(&segmentFlag, "segment", "", "Bump the named segment of the versioning scheme, e.g. build")
+	bumpCmd.Flags().BoolVar(&firstParentFlag, "first-parent", false, "Analyze only the first parent of merge commits")
+	bumpCmd.Flags().BoolVar(&mergesOnlyFlag, "merges-only", false, "Analyze only merge commit messages (e.g. pull request titles)")
+	bumpCmd.Flags().StringVar(&componentFlag, "component", "", "Bump only the given monorepo component")
+	bumpCmd.Flags().BoolVar(&allChangedFlag, "all-changed", false, "Bump every monorepo component with relevant changes")
 	bumpCmd.Flags().StringVar(&gitFlag, "git", "", "Auto Commit Bump version changes. Valid Values are COMMIT_TAG COMMIT_TAG_PUSH")
 }
 
+func applyCommitOptions() {
+	gitops.SetFirstParent(firstParentFlag || projectConfig.Commits.FirstParent)
+	gitops.SetMergesOnly(mergesOnlyFlag || projectConfig.Commits.MergesOnly)
+}
+
 func validateMode(values ...bool) bool {
 	trueCount := 0
 	for _, value := range values {
@@ -101,140 +176,122 @@ func validateMode(values ...bool) bool {
 	return trueCount == 1
 }
 
-func executeMajorMode() {
-	prints("bump major version")
-	err := version.BumpMajor()
-	if err != nil {
-		log.Fatal(err)
+func validateComponentMode() bool {
+	if allChangedFlag {
+		return componentFlag == "" && !majorFlag && !minorFlag && !patchFlag && !commitFlag && segmentFlag == ""
 	}
-	prints("bump major version success")
+	return !commitFlag && segmentFlag == "" && validateMode(majorFlag, minorFlag, patchFlag, autoFlag)
 }
 
-func executeMinorMode() {
-	prints("bump minor version")
-	err := version.BumpMinor()
-	if err != nil {
-		log.Fatal(err)
+func executeMajorMode() error {
+	slog.Debug("bump major version")
+	if err := version.BumpMajor(); err != nil {
+		return err
 	}
-	prints("bump minor version success")
+	slog.Debug("bump major version success")
+	return nil
 }
 
-func executePatchMode() {
-	prints("bump patch version success")
-	err := version.BumpPatch()
-	if err != nil {
-		log.Fatal(err)
+func executeMinorMode() error {
+	slog.Debug("bump minor version")
+	if err := version.BumpMinor(); err != nil {
+		return err
 	}
-	prints("bump patch version success")
+	slog.Debug("bump minor version success")
+	return nil
 }
 
-func executeAutoMode() {
-	prints("start auto mode")
+func executePatchMode() error {
+	slog.Debug("bump patch version")
+	if err := version.BumpPatch(); err != nil {
+		return err
+	}
+	slog.Debug("bump patch version success")
+	return nil
+}
+
+func executeSegmentMode() error {
+	slog.Debug("bump segment", "segment", segmentFlag)
+	if err := version.Bump(segmentFlag); err != nil {
+		return err
+	}
+	slog.Debug("bump segment success", "segment", segmentFlag)
+	return nil
+}
+
+func executeAutoMode() error {
+	slog.Debug("start auto mode")
 	bumpFunc, err := detectAutoBump()
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	err = bumpFunc()
-	if err != nil {
-		log.Fatal(err)
+	if err := bumpFunc(); err != nil {
+		return err
 	}
-	prints("start auto mode success")
+	slog.Debug("start auto mode success")
+	return nil
 }
 
-func executeCommitMode() {
-	prints("start commit mode")
+func executeCommitMode() error {
+	slog.Debug("start commit mode")
 	bumpFunc, err := detectCommitBump()
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	err = bumpFunc()
-	if err != nil {
-		log.Fatal(err)
+	if err := bumpFunc(); err != nil {
+		return err
 	}
-	prints("start commit mode success")
+	slog.Debug("start commit mode success")
+	return nil
 }
 
 func detectAutoBump() (func() error, error) {
-	prints("start detect bump function for auto mode")
-	tag, err := gitops.GetLastTag()
+	priority, err := detectAutoPriority()
 	if err != nil {
 		return nil, err
 	}
+	_, bump := priorityBump(priority)
+	return bump, nil
+}
 
-	if tag == fmt.Sprintf(constants.ReleaseTag, version.ToString()) {
-		return analyzeCommits(tag)
-	} else if tag == fmt.Sprintf(constants.VersionTag, version.ToString()) {
-		return analyzeAndCompareCommits(tag, fmt.Sprintf(constants.ReleaseTag, version.GetLastVersion()))
-	} else if tag == "" {
-		return analyzeCommits(tag)
-	} else {
-		return nil, fmt.Errorf("no new version required")
+// priorityBump returns the segment of the scheme and the bump of the version
+// for a priority other than PriorityNone.
+func priorityBump(priority int) (string, func() error) {
+	segment := version.LevelSegment(analyzer.Level(priority))
+	return segment, func() error {
+		return version.Bump(segment)
 	}
 }
 
-func analyzeCommits(tag string) (func() error, error) {
-	logf("analyze commits from head to %s...", tag)
-	commits, err := gitops.GetCommits(tag)
+// detectAutoPriority returns the bump priority of the commits since the last
+// tag. It returns an error wrapping ErrNoChange if no bump is required.
+func detectAutoPriority() (int, error) {
+	slog.Debug("start detect bump function for auto mode")
+	detection, err := analyzer.DetectPriority(gitHistory{}, version.ToString(), version.GetLastVersion(), slog.Default())
 	if err != nil {
-		log.Fatal(err)
+		return PriorityNone, err
 	}
-
-	logf("%d commits found", len(commits))
-
-	priority := findCommitPriority(commits)
-	logf("bump priority are: %d", priority)
-	switch priority {
-	case PriorityBreakingChange:
-		return version.BumpMajor, nil
-	case PriorityFeat:
-		return version.BumpMinor, nil
-	case PriorityFix:
-		return version.BumpPatch, nil
-	default:
-		return nil, fmt.Errorf("no new version required")
+	if detection.Priority == PriorityNone {
+		return PriorityNone, noChangeError(detection.Tag)
 	}
+	return detection.Priority, nil
 }
 
-func analyzeAndCompareCommits(starttag, endtag string) (func() error, error) {
-	logf("analyze commits from %s to %s...", starttag, endtag)
-	oldCommits, err := gitops.GetCommitsBetweenTags(starttag, endtag)
-	if err != nil {
-		log.Fatal(err)
-	}
-	logf("%d commits found", len(oldCommits))
-
-	logf("analyze commits from head to %s...", starttag)
-	newCommits, err := gitops.GetCommits(starttag)
-	if err != nil {
-		log.Fatal(err)
-	}
-	logf("%d commits found", len(newCommits))
+// gitHistory is the history of the repository of the gitops package.
+type gitHistory struct{}
 
-	priority := comparePriority(findCommitPriority(oldCommits), findCommitPriority(newCommits))
-	logf("bump priority are: %d", priority)
-	switch priority {
-	case PriorityBreakingChange:
-		return version.BumpMajor, nil
-	case PriorityFeat:
-		return version.BumpMinor, nil
-	case PriorityFix:
-		return version.BumpPatch, nil
-	default:
-		return nil, fmt.Errorf("cannot detect bump function")
-	}
+func (gitHistory) GetLatestTag() (string, error) {
+	return gitops.GetLastTag()
 }
 
-func comparePriority(first, second int) int {
-	if second > first {
-		return second
-	}
-	return 0
+func (gitHistory) GetCommitsBetweenTags(startTag, endTag string) ([]*object.Commit, error) {
+	return gitops.GetCommitsBetweenTags(startTag, endTag)
 }
 
 func detectCommitBump() (func() error, error) {
 	commit, err := gitops.GetHeadCommit()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	if strings.Contains(commit.Message, "[bump]") {
@@ -247,49 +304,62 @@ func detectCommitBump() (func() error, error) {
 		return version.BumpPatch, nil
 	}
 
-	return nil, fmt.Errorf("no bump commanded")
+	return nil, fmt.Errorf("%w: no bump commanded in %s", exceptions.ErrNoChange, commit.Hash.String()[:7])
+}
+
+// noChangeError reports that the commits since the tag require no bump.
+func noChangeError(tag string) error {
+	if tag == "" {
+		return exceptions.ErrNoChange
+	}
+	return fmt.Errorf("%w since %s", exceptions.ErrNoChange, tag)
 }
 
-func executeGitOperations() {
+func executeGitOperations(tx *bumpTransaction) error {
 	if gitFlag == CommitTag || gitFlag == CommitTagPush {
 
+		if err := tx.recordHead(); err != nil {
+			return tx.fail(err)
+		}
+
+		if err := tx.run(lifecycle.PreCommit); err != nil {
+			return tx.fail(err)
+		}
+
 		if _, err := gitops.Add(); err != nil {
-			log.Fatal(err)
+			return tx.fail(err)
 		}
 
 		if err := gitops.Commit(fmt.Sprintf(constants.CommitMessage, version.GetLastVersion(), version.ToString()), amend); err != nil {
-			log.Fatal(err)
+			return tx.fail(err)
 		}
 
-		if err := gitops.CreateTag(fmt.Sprintf(constants.VersionTag, version.ToString()), constants.TagMessage); err != nil {
-			log.Fatal(err)
+		tag := fmt.Sprintf(constants.VersionTag, version.ToString())
+		if err := gitops.CreateTag(tag, constants.TagMessage); err != nil {
+			return tx.fail(err)
+		}
+		tx.tags = append(tx.tags, tag)
+
+		tx.setTags(tag)
+		if err := tx.run(lifecycle.PostTag); err != nil {
+			return tx.fail(err)
 		}
 	}
 
 	if gitFlag == CommitTagPush {
 		if err := gitops.Push(); err != nil {
-			log.Fatal(err)
+			return tx.fail(err)
 		}
-	}
-}
+		tx.pushed = true
 
-func findCommitPriority(commits []*object.Commit) int {
-	highestPriority := PriorityNone
-
-	for _, commit := range commits {
-		message := commit.Message
-
-		if strings.Contains(message, "BREAKING CHANGE:") {
-			logf("BREAKING CHANGE found: %s", commit.Hash)
-			return PriorityBreakingChange
-		} else if strings.Contains(message, "feat:") && highestPriority < PriorityFeat {
-			logf("FEATURE found: %s", commit.Hash)
-			highestPriority = PriorityFeat
-		} else if strings.Contains(message, "fix:") && highestPriority < PriorityFix {
-			logf("FIX found: %s", commit.Hash)
-			highestPriority = PriorityFix
+		if err := tx.run(lifecycle.PostPush); err != nil {
+			return tx.fail(err)
 		}
 	}
 
-	return highestPriority
+	return nil
+}
+
+func findCommitPriority(commits []*object.Commit) int {
+	return analyzer.CommitPriority(commits, slog.Default())
 }
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"gotver/internal/constants"
+	"gotver/internal/exceptions"
+	"gotver/internal/gitops"
+	"gotver/internal/gittest"
+	"gotver/internal/version"
+	"path/filepath"
+	"testing"
+)
+
+const testConfig = "schemaVersion: 1\n"
+
+func TestDetectAutoBump(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		current string
+		last    string
+		want    string
+		// noChange expects that no bump is required.
+		noChange bool
+	}{
+		{
+			name:    "untagged feature",
+			script:  "commit feat: initial",
+			current: "0.1.0",
+			last:    "0.0.0",
+			want:    "0.2.0",
+		},
+		{
+			name: "fix since release",
+			script: `
+				commit feat: initial
+				tag r1.0.0
+				commit fix: bug`,
+			current: "1.0.0",
+			last:    "0.9.0",
+			want:    "1.0.1",
+		},
+		{
+			name: "feature and fix since release",
+			script: `
+				commit feat: initial
+				tag r1.0.0
+				commit fix: bug
+				commit feat: new`,
+			current: "1.0.0",
+			last:    "0.9.0",
+			want:    "1.1.0",
+		},
+		{
+			name: "breaking change since release",
+			script: `
+				commit feat: initial
+				tag r1.0.0
+				commit feat: api\n\nBREAKING CHANGE: removed the old api`,
+			current: "1.0.0",
+			last:    "0.9.0",
+			want:    "2.0.0",
+		},
+		{
+			name: "reverted feature",
+			script: `
+				commit feat: initial
+				tag r1.0.0
+				commit fix: bug
+				commit feat: new
+				revert HEAD`,
+			current: "1.0.0",
+			last:    "0.9.0",
+			want:    "1.0.1",
+		},
+		{
+			name: "nothing relevant since release",
+			script: `
+				commit feat: initial
+				tag r1.0.0
+				commit chore: update docs`,
+			current:  "1.0.0",
+			last:     "0.9.0",
+			noChange: true,
+		},
+		{
+			name: "more significant change since version tag",
+			script: `
+				commit feat: initial
+				tag r1.0.0
+				commit feat: new
+				tag v1.1.0
+				commit feat: api\n\nBREAKING CHANGE: removed the old api`,
+			current: "1.1.0",
+			last:    "1.0.0",
+			want:    "2.0.0",
+		},
+		{
+			name: "no more significant change since version tag",
+			script: `
+				commit feat: initial
+				tag r1.0.0
+				commit feat: new
+				tag v1.1.0
+				commit fix: bug`,
+			current:  "1.1.0",
+			last:     "1.0.0",
+			noChange: true,
+		},
+		{
+			name: "latest tag is not the current version",
+			script: `
+				commit feat: initial
+				tag v0.9.0
+				commit feat: new`,
+			current:  "1.0.0",
+			last:     "0.9.0",
+			noChange: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := gittest.New(t).Run(tt.script)
+			setupProject(t, repo, tt.current, tt.last, testConfig)
+			version.SetDeferWrite(true)
+
+			bump, err := detectAutoBump()
+			if tt.noChange {
+				if !errors.Is(err, exceptions.ErrNoChange) {
+					t.Fatalf("detectAutoBump() error = %v, want %v", err, exceptions.ErrNoChange)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("detectAutoBump() error = %v", err)
+			}
+
+			if err := bump(); err != nil {
+				t.Fatalf("bump error = %v", err)
+			}
+			if got := version.ToString(); got != tt.want {
+				t.Errorf("version = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// bumpAndWrite bumps the patch version and writes the version files like
+// the bump command does before the git operations.
+func bumpAndWrite(t *testing.T) *bumpTransaction {
+	t.Helper()
+
+	tx, err := newBumpTransaction()
+	if err != nil {
+		t.Fatalf("newBumpTransaction() error = %v", err)
+	}
+
+	version.SetDeferWrite(true)
+	if err := version.BumpPatch(); err != nil {
+		t.Fatalf("BumpPatch() error = %v", err)
+	}
+	tx.setVersions(version.GetLastVersion(), version.ToString())
+
+	tx.snapshot(version.GetFiles()...)
+	if err := version.WriteVersion(); err != nil {
+		t.Fatalf("WriteVersion() error = %v", err)
+	}
+	return tx
+}
+
+func setGitFlag(t *testing.T, value string) {
+	t.Helper()
+
+	previous := gitFlag
+	gitFlag = value
+	t.Cleanup(func() { gitFlag = previous })
+}
+
+func TestExecuteGitOperations(t *testing.T) {
+	for _, mode := range []string{CommitTag, CommitTagPush} {
+		t.Run(mode, func(t *testing.T) {
+			repo := gittest.New(t).Run(`
+				commit feat: initial
+				tag v1.0.0`)
+			remote := repo.AddRemote("origin")
+			setupProject(t, repo, "1.0.0", "0.9.0", testConfig)
+			setGitFlag(t, mode)
+
+			previousHead := repo.Head()
+			if err := executeGitOperations(bumpAndWrite(t)); err != nil {
+				t.Fatalf("executeGitOperations() error = %v", err)
+			}
+
+			head, err := gitops.GetHeadCommit()
+			if err != nil {
+				t.Fatalf("GetHeadCommit() error = %v", err)
+			}
+			if want := fmt.Sprintf(constants.CommitMessage, "1.0.0", "1.0.1"); head.Message != want {
+				t.Errorf("commit message = %q, want %q", head.Message, want)
+			}
+			if head.NumParents() != 1 || head.ParentHashes[0] != previousHead {
+				t.Errorf("commit parents = %v, want [%s]", head.ParentHashes, previousHead)
+			}
+
+			if got := repo.Resolve("v1.0.1"); got != head.Hash {
+				t.Errorf("tag v1.0.1 = %s, want %s", got, head.Hash)
+			}
+
+			pushed := mode == CommitTagPush
+			if got := remote.Branch(gittest.DefaultBranch) == head.Hash; got != pushed {
+				t.Errorf("branch pushed = %t, want %t", got, pushed)
+			}
+		})
+	}
+}
+
+func TestExecuteGitOperationsRollback(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag v1.0.0`)
+	remote := repo.AddRemote("origin")
+	fs := setupProject(t, repo, "1.0.0", "0.9.0", testConfig)
+	setGitFlag(t, CommitTagPush)
+	remote.Disconnect()
+
+	previousHead := repo.Head()
+	err := executeGitOperations(bumpAndWrite(t))
+	if !errors.Is(err, exceptions.ErrRemote) {
+		t.Fatalf("executeGitOperations() error = %v, want %v", err, exceptions.ErrRemote)
+	}
+	if got := exceptions.ExitCode(err); got != exceptions.ExitRemote {
+		t.Errorf("exit code = %d, want %d", got, exceptions.ExitRemote)
+	}
+
+	if got := repo.Head(); got != previousHead {
+		t.Errorf("HEAD = %s, want %s", got, previousHead)
+	}
+	if gitops.HasTag("v1.0.1") {
+		t.Error("tag v1.0.1 was not deleted")
+	}
+
+	dir := filepath.Join(testProjectDir, constants.ConfigFolderName)
+	if got := readFile(t, fs, filepath.Join(dir, constants.VersionFileName)); got != "1.0.0" {
+		t.Errorf("version file = %q, want %q", got, "1.0.0")
+	}
+	if got := readFile(t, fs, filepath.Join(dir, ".lastversion")); got != "0.9.0" {
+		t.Errorf("last version file = %q, want %q", got, "0.9.0")
+	}
+}
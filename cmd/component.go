@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"gotver/internal/component"
+	"gotver/internal/constants"
+	"gotver/internal/exceptions"
+	"gotver/internal/gitops"
+	"gotver/internal/lifecycle"
+	"gotver/internal/policy"
+	"gotver/internal/version"
+	"log/slog"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	message0003 = "Component bumped"
+	message0004 = "Component has no relevant changes"
+	message0006 = "Component bumped because a dependency changed"
+)
+
+// componentBump holds the state of a component during a bump.
+type componentBump struct {
+	component component.Component
+	version   *version.Version
+}
+
+func executeComponentMode() error {
+	if err := readConfig(); err != nil {
+		return err
+	}
+
+	components, err := loadComponents()
+	if err != nil {
+		return err
+	}
+
+	propagate, err := propagationPriority()
+	if err != nil {
+		return err
+	}
+
+	p, branch, err := resolveBranchPolicy()
+	if err != nil {
+		return err
+	}
+
+	applyCommitOptions()
+
+	if gitFlag == CommitTag || gitFlag == CommitTagPush || autoFlag || allChangedFlag {
+		if err := prepareGitOperation(); err != nil {
+			return err
+		}
+	}
+
+	selected := components
+	if componentFlag != "" {
+		c, err := component.Find(components, componentFlag)
+		if err != nil {
+			return err
+		}
+		selected = []component.Component{c}
+	}
+
+	bumps := make(map[string]componentBump)
+	getBump := func(c component.Component) (componentBump, error) {
+		if cb, ok := bumps[c.Name]; ok {
+			return cb, nil
+		}
+		cb, err := newComponentBump(c, p, branch)
+		if err != nil {
+			return componentBump{}, err
+		}
+		bumps[c.Name] = cb
+		return cb, nil
+	}
+
+	priorities := make(map[string]int)
+	for _, c := range selected {
+		cb, err := getBump(c)
+		if err != nil {
+			return err
+		}
+
+		priority, err := detectComponentPriority(cb)
+		if err != nil {
+			return err
+		}
+
+		if priority == PriorityNone {
+			slog.Info(message0004, "component", c.Name)
+			continue
+		}
+		priorities[c.Name] = priority
+	}
+
+	steps, err := component.Plan(components, priorities, propagate)
+	if err != nil {
+		return err
+	}
+
+	tx, err := newBumpTransaction()
+	if err != nil {
+		return err
+	}
+
+	var bumped []componentBump
+	done := make(map[string]bool)
+	for _, step := range steps {
+		cb, err := getBump(step.Component)
+		if err != nil {
+			return tx.fail(err)
+		}
+		if step.Propagated {
+			slog.Info(message0006, "component", step.Component.Name)
+		}
+
+		if err := bumpFunction(cb.version, step.Level)(); err != nil {
+			return tx.fail(err)
+		}
+
+		tx.setComponent(cb.component.Name)
+		tx.setVersions(cb.version.GetLastVersion(), cb.version.ToString())
+		if err := tx.run(lifecycle.PreBump); err != nil {
+			return tx.fail(err)
+		}
+
+		for _, dep := range step.Component.Dependencies {
+			if !done[dep.Name] {
+				continue
+			}
+			depBump := bumps[dep.Name]
+			slog.Debug("update dependency reference", "dependency", dep.Name, "version", depBump.version.ToString(), "file", dep.File)
+			if dep.File != "" {
+				tx.snapshot(filepath.Join(projectDir, dep.File))
+			}
+			if err := dep.UpdateReference(filesystem, projectDir, depBump.version.ToString()); err != nil {
+				return tx.fail(err)
+			}
+		}
+
+		tx.snapshot(cb.version.GetFiles()...)
+		if err := cb.version.WriteVersion(); err != nil {
+			return tx.fail(err)
+		}
+
+		if err := tx.run(lifecycle.PostVersionWrite); err != nil {
+			return tx.fail(err)
+		}
+
+		bumped = append(bumped, cb)
+		done[step.Component.Name] = true
+	}
+
+	if len(bumped) == 0 {
+		return nil
+	}
+
+	tx.setComponent("")
+	tx.setVersions("", "")
+	if err := executeComponentGitOperations(tx, bumped); err != nil {
+		return err
+	}
+
+	for _, cb := range bumped {
+		slog.Info(message0003, "component", cb.component.Name, "from", cb.version.GetLastVersion(), "to", cb.version.ToString())
+	}
+	return nil
+}
+
+// propagationPriority returns the bump priority dependents of a bumped
+// component receive.
+func propagationPriority() (int, error) {
+	switch level := projectConfig.Propagation; level {
+	case "none":
+		return PriorityNone, nil
+	case "", version.SegmentPatch:
+		return PriorityFix, nil
+	case version.SegmentMinor:
+		return PriorityFeat, nil
+	case version.SegmentMajor:
+		return PriorityBreakingChange, nil
+	default:
+		return PriorityNone, fmt.Errorf("%w: invalid propagation level %q", exceptions.ErrInvalidConfig, level)
+	}
+}
+
+func bumpFunction(v *version.Version, priority int) func() error {
+	switch priority {
+	case PriorityBreakingChange:
+		return v.BumpMajor
+	case PriorityFeat:
+		return v.BumpMinor
+	default:
+		return v.BumpPatch
+	}
+}
+
+func loadComponents() ([]component.Component, error) {
+	components := projectConfig.Components
+	if len(components) == 0 {
+		return nil, fmt.Errorf("%w: no components configured", exceptions.ErrInvalidConfig)
+	}
+
+	if err := component.ValidateAll(components); err != nil {
+		return nil, err
+	}
+
+	return components, nil
+}
+
+func newComponentBump(c component.Component, p *policy.Policy, branch string) (componentBump, error) {
+	v, err := readComponentVersion(c)
+	if err != nil {
+		return componentBump{}, err
+	}
+	v.SetDeferWrite(true)
+
+	if p != nil {
+		v.SetPreReleaseIdentifier(p.PreReleaseIdentifier())
+		v.SetBumpLimit(p.MaxBump)
+		if line := p.VersionLine(branch); line != "" {
+			if err := v.SetVersionLine(line); err != nil {
+				return componentBump{}, err
+			}
+		}
+	}
+
+	return componentBump{component: c, version: v}, nil
+}
+
+// readComponentVersion reads the version file of a component.
+func readComponentVersion(c component.Component) (*version.Version, error) {
+	versionFile := filepath.Join(projectDir, c.VersionFilePath())
+
+	scheme, err := loadScheme()
+	if err != nil {
+		return nil, err
+	}
+
+	v := version.New()
+	v.SetFs(filesystem)
+	v.SetScheme(scheme)
+	v.SetFilePath(filepath.Dir(versionFile))
+	v.SetFileName(filepath.Base(versionFile))
+	if err := v.ReadVersion(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// detectComponentPriority returns the bump priority of a component.
+// PriorityNone means the component has no relevant changes.
+func detectComponentPriority(cb componentBump) (int, error) {
+	switch {
+	case majorFlag:
+		return PriorityBreakingChange, nil
+	case minorFlag:
+		return PriorityFeat, nil
+	case patchFlag:
+		return PriorityFix, nil
+	}
+
+	tag, err := cb.component.Tag(cb.version.ToString())
+	if err != nil {
+		return PriorityNone, err
+	}
+	if !gitops.HasTag(tag) {
+		tag = ""
+	}
+
+	slog.Debug("analyze commits from head", "component", cb.component.Name, "tag", tag)
+	commits, err := gitops.GetCommits(tag)
+	if err != nil {
+		return PriorityNone, err
+	}
+
+	relevant, err := filterComponentCommits(cb.component, commits)
+	if err != nil {
+		return PriorityNone, err
+	}
+	slog.Debug("commits touching component found", "component", cb.component.Name, "path", cb.component.Path, "count", len(relevant), "total", len(commits))
+
+	priority := findCommitPriority(relevant)
+	slog.Debug("bump priority detected", "priority", priority)
+	if priority == PriorityNone && !allChangedFlag {
+		return PriorityNone, noChangeError(tag)
+	}
+	return priority, nil
+}
+
+func filterComponentCommits(c component.Component, commits []*object.Commit) ([]*object.Commit, error) {
+	var relevant []*object.Commit
+	for _, commit := range commits {
+		files, err := gitops.GetChangedFiles(commit)
+		if err != nil {
+			return nil, err
+		}
+		if c.Touches(files) {
+			relevant = append(relevant, commit)
+		}
+	}
+	return relevant, nil
+}
+
+func executeComponentGitOperations(tx *bumpTransaction, bumped []componentBump) error {
+	if gitFlag == CommitTag || gitFlag == CommitTagPush {
+
+		if err := tx.recordHead(); err != nil {
+			return tx.fail(err)
+		}
+
+		if err := tx.run(lifecycle.PreCommit); err != nil {
+			return tx.fail(err)
+		}
+
+		if _, err := gitops.Add(); err != nil {
+			return tx.fail(err)
+		}
+
+		changes := make([]string, 0, len(bumped))
+		for _, cb := range bumped {
+			changes = append(changes, fmt.Sprintf(constants.ComponentChange, cb.component.Name, cb.version.GetLastVersion(), cb.version.ToString()))
+		}
+
+		if err := gitops.Commit(fmt.Sprintf(constants.ComponentCommitMessage, strings.Join(changes, ", ")), amend); err != nil {
+			return tx.fail(err)
+		}
+
+		for _, cb := range bumped {
+			tag, err := cb.component.Tag(cb.version.ToString())
+			if err != nil {
+				return tx.fail(err)
+			}
+			if err := gitops.CreateTag(tag, constants.TagMessage); err != nil {
+				return tx.fail(err)
+			}
+			tx.tags = append(tx.tags, tag)
+		}
+
+		tx.setTags(tx.tags...)
+		if err := tx.run(lifecycle.PostTag); err != nil {
+			return tx.fail(err)
+		}
+	}
+
+	if gitFlag == CommitTagPush {
+		if err := gitops.Push(); err != nil {
+			return tx.fail(err)
+		}
+		tx.pushed = true
+
+		if err := tx.run(lifecycle.PostPush); err != nil {
+			return tx.fail(err)
+		}
+	}
+
+	return nil
+}
@@ -1,31 +1,349 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gotver/internal/config"
+	"gotver/internal/constants"
+	"gotver/internal/exceptions"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+var (
+	configMigrateDryRunFlag bool
 )
 
 // configCmd represents the config command
 var configCmd = &cobra.Command{
 	Use:   "config",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+	Short: "Show, change and check the configuration",
+	Long: `Show, change and check the configuration of the project in
+.gitver/config.yaml.
+
+Keys are dotted paths like commits.firstParent, "gitver config list" shows
+all supported keys with their effective values and where each value comes
+from.
+
+Example:
+  gitver config list
+  gitver config get scheme.name
+  gitver config set commits.firstParent true
+  gitver config validate
+  gitver config migrate
+  gitver config schema`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of a key",
+	Long: `Print the effective value of a key: the overridden value, the configured
+value or the default. Lists and objects are printed as JSON.
+
+Example:
+  gitver config get scheme.name
+  gitver config get commits.types`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := readOptionalConfig(); err != nil {
+			return err
+		}
+
+		key, schema, err := config.Lookup(args[0])
+		if err != nil {
+			return err
+		}
+
+		value, _ := configValue(key, schema)
+		fmt.Fprintln(cmd.OutOrStdout(), formatConfigValue(value))
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a key in the configuration file",
+	Long: `Set a key in .gitver/config.yaml. The value is given in YAML syntax and
+checked against the type of the key. Missing parent keys are created, all
+other keys and comments of the file are kept.
+
+Example:
+  gitver config set scheme.name calver
+  gitver config set commits.headerMaxLength 72
+  gitver config set commits.types "[feat, fix, docs]"`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, _, err := config.Lookup(args[0])
+		if err != nil {
+			return err
+		}
+
+		file := configFile()
+		data, err := afero.ReadFile(filesystem, file)
+		if err != nil {
+			return configError(err)
+		}
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+		document, err := config.Parse(data)
+		if err != nil {
+			return err
+		}
+		if err := document.Set(key, args[1]); err != nil {
+			return err
+		}
+
+		if data, err = document.Bytes(); err != nil {
+			return err
+		}
+		if err := afero.WriteFile(filesystem, file, data, 0o644); err != nil {
+			return fmt.Errorf("%w: %w", exceptions.ErrIO, err)
+		}
+
+		slog.Debug("configuration written", "file", file, "key", key)
+		fmt.Fprintf(cmd.OutOrStdout(), "%s set to %s\n", key, args[1])
+		return nil
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the effective values of all keys and their sources",
+	Long: `List all supported keys with their effective value and its source:
+  flag     the key is overridden with --set or a flag bound to it
+  env      the key is overridden with an environment variable
+  project  the key is set in .gitver/config.yaml
+  user     the key is set in the user configuration
+  default  the key is not configured, the default applies
+
+Example:
+  gitver config list`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := readOptionalConfig(); err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		for _, key := range config.Keys() {
+			_, schema, _ := config.Lookup(key)
+			value, source := configValue(key, schema)
+			fmt.Fprintf(out, "%-26s%-9s%s\n", key, source, formatConfigValue(value))
+		}
+		return nil
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration file against the supported keys",
+	Long: `Check .gitver/config.yaml against the supported keys, their types and
+allowed values. Every problem is reported with its line and column and the
+command exits with 2 if there is any.
+
+Example:
+  gitver config validate`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return validateConfigFile(cmd.OutOrStdout(), configFile())
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the configuration file in an editor",
+	Long: `Open .gitver/config.yaml in the editor of $VISUAL or $EDITOR, vi by
+default, and validate the file when the editor exits.
+
+Example:
+  EDITOR="code --wait" gitver config edit`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := configFile()
+		if exists, err := afero.Exists(filesystem, file); err != nil || !exists {
+			return configError(fmt.Errorf("configuration file %s not found", file))
+		}
+
+		editor := editorCommand()
+		slog.Debug("open editor", "editor", editor, "file", file)
+
+		command := exec.Command(editor[0], append(editor[1:], file)...)
+		command.Stdin = os.Stdin
+		command.Stdout = cmd.OutOrStdout()
+		command.Stderr = cmd.ErrOrStderr()
+		if err := command.Run(); err != nil {
+			return fmt.Errorf("editor %s failed: %w", editor[0], err)
+		}
+
+		return validateConfigFile(cmd.OutOrStdout(), file)
+	},
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the configuration file to the current schema version",
+	Long: `Upgrade .gitver/config.yaml from its schemaVersion to the schema version
+of this gitver. Files without schemaVersion have version 0. The migrations
+are applied in order, the change is printed as a diff and the original file
+is kept as .gitver/config.yaml.v<schemaVersion>.bak.
+
+Example:
+  gitver config migrate --dry-run
+  gitver config migrate`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return migrateConfigFile(cmd.OutOrStdout(), configFile(), configMigrateDryRunFlag)
+	},
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the configuration file",
+	Long: `Print the JSON Schema of .gitver/config.yaml, so editors can validate and
+complete the file. With the YAML language server a schema file is used by
+a comment in the first line of the configuration:
+  # yaml-language-server: $schema=gitver.schema.json
+
+Example:
+  gitver config schema > .gitver/gitver.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := config.JSONSchema()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	},
 }
 
 func init() {
 	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd, configSetCmd, configListCmd, configValidateCmd, configEditCmd, configMigrateCmd, configSchemaCmd)
+	configMigrateCmd.Flags().BoolVar(&configMigrateDryRunFlag, "dry-run", false, "Print the changes without writing them")
+}
+
+// configFile returns the path of the configuration file of the project.
+func configFile() string {
+	return filepath.Join(projectDir, constants.ConfigFolderName, constants.ConfigName+"."+constants.ConfigType)
+}
+
+// configValue returns the effective value of a key and its source.
+func configValue(key string, schema *config.Schema) (any, string) {
+	source := configSource(key)
+	if source == config.SourceDefault {
+		return schema.Default, source
+	}
+	return viper.Get(key), source
+}
 
-	// Here you will define your flags and configuration settings.
+// formatConfigValue prints scalars as they are and lists and objects as
+// JSON.
+func formatConfigValue(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string, bool, int:
+		return fmt.Sprint(value)
+	}
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// configCmd.PersistentFlags().String("foo", "", "A help for foo")
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// validateConfigFile prints the problems of a configuration file and ends
+// with exit code 2 if there is any.
+func validateConfigFile(out io.Writer, file string) error {
+	data, err := afero.ReadFile(filesystem, file)
+	if err != nil {
+		return configError(err)
+	}
+
+	problems, err := config.Validate(data)
+	if err != nil {
+		return err
+	}
+
+	if len(problems) == 0 {
+		fmt.Fprintf(out, "%s is valid\n", file)
+		return nil
+	}
+	for _, problem := range problems {
+		fmt.Fprintf(out, "%s:%s\n", file, problem)
+	}
+	fmt.Fprintf(out, "%d problems found\n", len(problems))
+	return exitError(exceptions.ExitValidation)
+}
+
+// migrateConfigFile upgrades a configuration file to the current schema
+// version, prints the diff and writes a backup of the original file.
+func migrateConfigFile(out io.Writer, file string, dryRun bool) error {
+	data, err := afero.ReadFile(filesystem, file)
+	if err != nil {
+		return configError(err)
+	}
+
+	document, err := config.Parse(data)
+	if err != nil {
+		return err
+	}
+	from, err := document.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	applied, err := document.Migrate()
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Fprintf(out, "%s is up to date with schema version %d\n", file, from)
+		return nil
+	}
+
+	migrated, err := document.Bytes()
+	if err != nil {
+		return err
+	}
+	for _, m := range applied {
+		fmt.Fprintf(out, "schema version %d -> %d: %s\n", m.From, m.From+1, m.Description)
+	}
+	fmt.Fprint(out, config.Diff(file, file, data, migrated))
+
+	if dryRun {
+		return nil
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", file, from)
+	if err := afero.WriteFile(filesystem, backup, data, 0o644); err != nil {
+		return fmt.Errorf("%w: %w", exceptions.ErrIO, err)
+	}
+	if err := afero.WriteFile(filesystem, file, migrated, 0o644); err != nil {
+		return fmt.Errorf("%w: %w", exceptions.ErrIO, err)
+	}
+	slog.Info("configuration migrated", "file", file, "backup", backup, "schemaVersion", config.SchemaVersion)
+	return nil
+}
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// configCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+// editorCommand returns the editor of $VISUAL or $EDITOR and its arguments.
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.Fields(os.Getenv(name)); len(editor) > 0 {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
 }
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"github.com/spf13/afero"
+	"gotver/internal/config"
+	"gotver/internal/gittest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigValue(t *testing.T) {
+	repo := gittest.New(t).Run("commit feat: initial")
+	setupProject(t, repo, "1.0.0", "0.9.0", testConfig+"commits:\n  headerMaxLength: 72\n")
+
+	tests := []struct {
+		key        string
+		want       string
+		wantSource string
+	}{
+		{"schemaVersion", "1", config.SourceProject},
+		{"version", "", config.SourceDefault},
+		{"commits.headerMaxLength", "72", config.SourceProject},
+		{"commits.firstParent", "false", config.SourceDefault},
+		{"scheme.name", "semver", config.SourceDefault},
+		{"branches", "", config.SourceDefault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			_, schema, err := config.Lookup(tt.key)
+			if err != nil {
+				t.Fatalf("Lookup() error = %v", err)
+			}
+
+			value, source := configValue(tt.key, schema)
+			if got := formatConfigValue(value); got != tt.want || source != tt.wantSource {
+				t.Errorf("configValue() = %q from %s, want %q from %s", got, source, tt.want, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestConfigOverrides(t *testing.T) {
+	repo := gittest.New(t).Run("commit feat: initial")
+	fs := setupProject(t, repo, "1.0.0", "0.9.0", testConfig+"commits:\n  headerMaxLength: 72\n  mergesOnly: true\n")
+
+	previousUserConfig := userConfigFile
+	userConfigFile = "/home/user/.config/gitver/config.yaml"
+	t.Cleanup(func() { userConfigFile, setFlag, setKeys, userConfig = previousUserConfig, nil, nil, nil })
+	user := "commits:\n  headerMaxLength: 50\n  scopes: [api]\npropagation: minor\n"
+	if err := afero.WriteFile(fs, userConfigFile, []byte(user), 0o644); err != nil {
+		t.Fatalf("write user config: %v", err)
+	}
+
+	t.Setenv("GITVER_COMMITS_MERGESONLY", "false")
+	t.Setenv("GITVER_COMMITS_TYPES", "[feat, fix]")
+	t.Setenv("GITVER_BRANCHES", `[{"pattern": "main", "type": "release"}]`)
+	t.Setenv("GITVER_PROPAGATION", "major")
+	setFlag = []string{"propagation=none", "hooks.pre-bump=[make test]"}
+	if err := applySetFlag(); err != nil {
+		t.Fatalf("applySetFlag() error = %v", err)
+	}
+	if err := readConfig(); err != nil {
+		t.Fatalf("readConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		key        string
+		want       string
+		wantSource string
+	}{
+		{"propagation", "none", config.SourceFlag},
+		{"hooks", `{"pre-bump":"[make test]"}`, config.SourceFlag},
+		{"commits.mergesOnly", "false", config.SourceEnv},
+		{"commits.types", "[feat, fix]", config.SourceEnv},
+		{"commits.headerMaxLength", "72", config.SourceProject},
+		{"commits.scopes", `["api"]`, config.SourceUser},
+		{"commits.firstParent", "false", config.SourceDefault},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			_, schema, _ := config.Lookup(tt.key)
+			value, source := configValue(tt.key, schema)
+			if got := formatConfigValue(value); got != tt.want || source != tt.wantSource {
+				t.Errorf("configValue() = %q from %s, want %q from %s", got, source, tt.want, tt.wantSource)
+			}
+		})
+	}
+
+	c := projectConfig
+	if c.Propagation != "none" || c.Commits.MergesOnly || c.Commits.HeaderMaxLength != 72 ||
+		!reflect.DeepEqual(c.Commits.Types, []string{"feat", "fix"}) ||
+		!reflect.DeepEqual(c.Commits.Scopes, []string{"api"}) ||
+		!reflect.DeepEqual(c.Hooks["pre-bump"], []string{"make test"}) ||
+		len(c.Branches) != 1 || c.Branches[0].Type != "release" {
+		t.Errorf("projectConfig = %+v, want the overrides applied", c)
+	}
+}
+
+func TestConfigSetFlagInvalid(t *testing.T) {
+	t.Cleanup(func() { setFlag, setKeys = nil, nil })
+
+	for _, setting := range []string{"commits.firstParent", "commits.firstParnet=true"} {
+		setFlag = []string{setting}
+		if err := applySetFlag(); err == nil {
+			t.Errorf("applySetFlag(%q) error = nil, want an error", setting)
+		}
+	}
+}
+
+func TestConfigSet(t *testing.T) {
+	repo := gittest.New(t).Run("commit feat: initial")
+	fs := setupProject(t, repo, "1.0.0", "0.9.0", "# project\n"+testConfig)
+
+	var out bytes.Buffer
+	configSetCmd.SetOut(&out)
+	t.Cleanup(func() { configSetCmd.SetOut(nil) })
+	if err := configSetCmd.RunE(configSetCmd, []string{"commits.mergesOnly", "true"}); err != nil {
+		t.Fatalf("config set error = %v", err)
+	}
+
+	want := "# project\nschemaVersion: 1\ncommits:\n  mergesOnly: true\n"
+	if got := readFile(t, fs, configFile()); got != want {
+		t.Errorf("config file = %q, want %q", got, want)
+	}
+
+	if err := validateConfigFile(&out, configFile()); err != nil {
+		t.Errorf("validateConfigFile() error = %v", err)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	repo := gittest.New(t).Run("commit feat: initial")
+	fs := setupProject(t, repo, "1.0.0", "0.9.0", testConfig)
+	if err := afero.WriteFile(fs, configFile(), []byte(testConfig+"commits:\n  firstParnet: true\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	var out bytes.Buffer
+	err := validateConfigFile(&out, configFile())
+
+	var exit exitError
+	if !errors.As(err, &exit) || exitCode(err) != 2 {
+		t.Errorf("validateConfigFile() error = %v, want exit code 2", err)
+	}
+	if want := configFile() + ":3:3: commits.firstParnet: unknown key"; !strings.Contains(out.String(), want) {
+		t.Errorf("output does not contain %q:\n%s", want, out.String())
+	}
+}
+
+func TestConfigMigrate(t *testing.T) {
+	repo := gittest.New(t).Run("commit feat: initial")
+	fs := setupProject(t, repo, "1.0.0", "0.9.0", testConfig)
+
+	legacy := "# project\nversion: 1.0.1\ncommits:\n  firstParent: true\n"
+	if err := afero.WriteFile(fs, configFile(), []byte(legacy), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := migrateConfigFile(&out, configFile(), true); err != nil {
+		t.Fatalf("migrateConfigFile() dry run error = %v", err)
+	}
+	if got := readFile(t, fs, configFile()); got != legacy {
+		t.Errorf("config file after dry run = %q, want %q", got, legacy)
+	}
+	if !strings.Contains(out.String(), "-version: 1.0.1\n+schemaVersion: 1\n") {
+		t.Errorf("output does not contain the diff:\n%s", out.String())
+	}
+
+	if err := migrateConfigFile(&out, configFile(), false); err != nil {
+		t.Fatalf("migrateConfigFile() error = %v", err)
+	}
+	if got, want := readFile(t, fs, configFile()), "# project\nschemaVersion: 1\ncommits:\n  firstParent: true\n"; got != want {
+		t.Errorf("config file = %q, want %q", got, want)
+	}
+	if got := readFile(t, fs, configFile()+".v0.bak"); got != legacy {
+		t.Errorf("backup = %q, want %q", got, legacy)
+	}
+
+	out.Reset()
+	if err := migrateConfigFile(&out, configFile(), false); err != nil || !strings.Contains(out.String(), "up to date") {
+		t.Errorf("migrateConfigFile() of a current file = %v:\n%s", err, out.String())
+	}
+}
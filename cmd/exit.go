@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"gotver/internal/exceptions"
+	"log/slog"
+)
+
+var (
+	noChangeExitCode int
+)
+
+// exitError ends a command with an exit code without logging an error,
+// e.g. when the command already printed the problems it found.
+type exitError int
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+// usageError reports an invalid combination of arguments and flags.
+func usageError(message string) error {
+	return fmt.Errorf("%w: %s", exceptions.ErrInvalidInput, message)
+}
+
+// configError reports a configuration that could not be read or decoded.
+func configError(err error) error {
+	return fmt.Errorf("%w: %w", exceptions.ErrInvalidConfig, err)
+}
+
+// exitCode logs the error a command failed with and returns the process
+// exit code for it. That no new version is required is not a failure and
+// exits with --no-change-exit-code.
+func exitCode(err error) int {
+	var exit exitError
+	switch {
+	case err == nil:
+		return exceptions.ExitOK
+	case errors.As(err, &exit):
+		return int(exit)
+	case errors.Is(err, exceptions.ErrNoChange):
+		slog.Info(err.Error())
+		return noChangeExitCode
+	}
+
+	if code := exceptions.CodeOf(err); code != 0 {
+		slog.Error(err.Error(), "code", int(code))
+	} else {
+		slog.Error(err.Error())
+	}
+	return exceptions.ExitCode(err)
+}
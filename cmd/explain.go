@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"gotver/internal/exceptions"
+	"strconv"
+	"strings"
+)
+
+// explainCmd represents the explain command
+var explainCmd = &cobra.Command{
+	Use:   "explain [code]",
+	Short: "Explain an error code",
+	Long: `Explain an error code printed as "error code: <code>".
+
+Prints what the error means, how to fix it and the exit code gitver
+terminates with. Without a code all error codes are listed.
+
+Example:
+  gitver explain 2002`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			for _, info := range exceptions.All() {
+				fmt.Fprintf(cmd.OutOrStdout(), "%d\t%s\t%s\n", info.Code, info.Name, info.Description)
+			}
+			return nil
+		}
+
+		code, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(args[0]), "E"))
+		if err != nil {
+			return fmt.Errorf("%w: error code %q is not a number", exceptions.ErrInvalidInput, args[0])
+		}
+
+		info, ok := exceptions.Lookup(exceptions.Code(code))
+		if !ok {
+			return fmt.Errorf("%w: unknown error code %d", exceptions.ErrInvalidInput, code)
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "%d %s (%s, exit code %d)\n\n", info.Code, info.Name, info.Kind, exceptions.ExitCode(info.Kind))
+		fmt.Fprintf(out, "%s\n\n", info.Description)
+		fmt.Fprintf(out, "Remediation: %s\n", info.Remediation)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+}
@@ -3,25 +3,74 @@ package cmd
 import (
 	"fmt"
 	"github.com/spf13/viper"
+	"gotver/internal/config"
 	"gotver/internal/gitops"
+	"gotver/internal/policy"
 	"gotver/internal/version"
-	"log"
+	"log/slog"
 )
 
-func loadConfig() {
-	prints("load configuration")
-	if err := viper.ReadInConfig(); err != nil {
-		log.Fatal(err)
+// projectConfig is the decoded configuration of the project, it is set by
+// readConfig and readOptionalConfig.
+var projectConfig = config.Default()
+
+func loadConfig() error {
+	if err := readConfig(); err != nil {
+		return err
+	}
+
+	scheme, err := loadScheme()
+	if err != nil {
+		return err
 	}
+	version.SetScheme(scheme)
 
 	if err := version.ReadVersion(); err != nil {
-		log.Fatal(err)
+		return err
+	}
+	slog.Debug("load configuration success", "file", viper.ConfigFileUsed())
+	return nil
+}
+
+func readConfig() error {
+	slog.Debug("load configuration")
+	if err := readUserConfig(); err != nil {
+		return err
+	}
+	if err := viper.ReadInConfig(); err != nil {
+		return configError(err)
+	}
+	if err := decodeConfig(); err != nil {
+		return err
+	}
+	return checkSchemaVersion()
+}
+
+// decodeConfig decodes the settings read by viper strictly into
+// projectConfig.
+func decodeConfig() error {
+	decoded, err := config.Decode(viper.AllSettings())
+	if err != nil {
+		return err
 	}
-	prints("load configuration success")
+	projectConfig = decoded
+	return nil
+}
+
+// checkSchemaVersion warns if the configuration file has an outdated layout.
+func checkSchemaVersion() error {
+	switch current := projectConfig.SchemaVersion; {
+	case current > config.SchemaVersion:
+		return config.NewerSchemaError(current)
+	case current < config.SchemaVersion:
+		slog.Warn("configuration is outdated, run gitver config migrate", "file", viper.ConfigFileUsed(),
+			"schemaVersion", current, "current", config.SchemaVersion)
+	}
+	return nil
 }
 
 func prepareGitOperation() error {
-	prints("perpare git operations")
+	slog.Debug("prepare git operations")
 	if err := gitops.ReadRepository(); err != nil {
 		return fmt.Errorf("git repository is not initialized: %w", err)
 	}
@@ -32,22 +81,70 @@ func prepareGitOperation() error {
 	}
 
 	if !isClean {
-		return fmt.Errorf("git repository is not clean")
+		return gitops.DirtyRepositoryError(projectDir)
 	}
 
-	prints("prepare git operations success")
+	slog.Debug("prepare git operations success")
 	return nil
 
 }
 
-func logf(format string, v ...any) {
-	if verbose {
-		log.Printf(format, v)
+// loadScheme returns the configured versioning scheme, SemVer by default.
+func loadScheme() (version.Scheme, error) {
+	scheme := projectConfig.Scheme
+	return version.NewScheme(scheme.Name, scheme.Format, scheme.Segments)
+}
+
+// applyBranchPolicy looks up the policy matching the HEAD branch and
+// configures the version accordingly. Without configured policies every
+// branch may be versioned.
+func applyBranchPolicy() error {
+	p, branch, err := resolveBranchPolicy()
+	if err != nil || p == nil {
+		return err
+	}
+
+	version.SetPreReleaseIdentifier(p.PreReleaseIdentifier())
+	version.SetBumpLimit(p.MaxBump)
+
+	if line := p.VersionLine(branch); line != "" {
+		slog.Debug("branch restricts version line", "branch", branch, "line", line)
+		if err := version.SetVersionLine(line); err != nil {
+			return err
+		}
 	}
+
+	slog.Debug("apply branch policy success")
+	return nil
 }
 
-func prints(v ...any) {
-	if verbose {
-		log.Print(v)
+// resolveBranchPolicy returns the policy matching the HEAD branch, or nil if
+// no policies are configured.
+func resolveBranchPolicy() (*policy.Policy, string, error) {
+	policies := projectConfig.Branches
+	if len(policies) == 0 {
+		return nil, "", nil
 	}
+
+	slog.Debug("apply branch policy")
+	if err := gitops.ReadRepository(); err != nil {
+		return nil, "", fmt.Errorf("git repository is not initialized: %w", err)
+	}
+
+	branch, err := gitops.GetBranchName()
+	if err != nil {
+		return nil, "", err
+	}
+
+	p, err := policy.Match(policies, branch)
+	if err != nil {
+		return nil, "", err
+	}
+	slog.Debug("branch policy matched", "branch", branch, "pattern", p.Pattern, "type", p.Type)
+
+	if !p.AllowsBump() {
+		return nil, "", policy.BranchNotAllowedError(branch)
+	}
+
+	return &p, branch, nil
 }
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"gotver/internal/constants"
+	"gotver/internal/gitops"
+	"gotver/internal/gittest"
+	"gotver/internal/version"
+	"path/filepath"
+	"testing"
+)
+
+// testProjectDir is the project directory in the in-memory filesystem.
+const testProjectDir = "/project"
+
+// setupProject points the command state at an in-memory project: the
+// configuration and the version files live in a memory filesystem, the git
+// repository is the one of repo. The project has the version and the last
+// version given and is loaded like by a command.
+func setupProject(t *testing.T, repo *gittest.Repo, current, last, config string) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	dir := filepath.Join(testProjectDir, constants.ConfigFolderName)
+	files := map[string]string{
+		constants.ConfigName + "." + constants.ConfigType: config,
+		constants.VersionFileName:                         current,
+		".lastversion":                                    last,
+	}
+	for name, content := range files {
+		if err := afero.WriteFile(fs, filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	previousFs, previousDir := filesystem, projectDir
+	t.Cleanup(func() {
+		filesystem, projectDir = previousFs, previousDir
+		viper.Reset()
+		version.SetFs(previousFs)
+		version.SetDeferWrite(false)
+	})
+
+	filesystem, projectDir = fs, testProjectDir
+	viper.Reset()
+	configureViper(dir)
+	version.SetFs(fs)
+	version.SetFilePath(dir)
+	version.SetFileName(constants.VersionFileName)
+
+	if err := gitops.SetRepository(repo.Repository); err != nil {
+		t.Fatalf("set repository: %v", err)
+	}
+
+	if err := loadConfig(); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	return fs
+}
+
+// readFile returns the content of a file of the in-memory filesystem.
+func readFile(t *testing.T, fs afero.Fs, name string) string {
+	t.Helper()
+
+	data, err := afero.ReadFile(fs, name)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(data)
+}
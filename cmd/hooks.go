@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"gotver/internal/constants"
+	"gotver/internal/gitops"
+	"gotver/internal/hooks"
+	"log/slog"
+)
+
+var (
+	prePushFlag bool
+)
+
+// hooksCmd represents the hooks command
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage git hooks invoking gitver",
+}
+
+// hooksInstallCmd represents the hooks install command
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install a commit-msg hook linting commit messages",
+	Long: `Install a commit-msg hook running gitver lint on every commit and, with
+--pre-push, a pre-push hook linting the commits being pushed.
+
+Existing hooks not written by gitver are kept as <hook>.local and run
+before the gitver hook.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := gitops.ReadRepository(); err != nil {
+			return fmt.Errorf("git repository is not initialized: %w", err)
+		}
+
+		dir, err := gitops.GetHooksDirectory()
+		if err != nil {
+			return err
+		}
+
+		names := []string{hooks.CommitMsg}
+		if prePushFlag {
+			names = append(names, hooks.PrePush)
+		}
+
+		for _, name := range names {
+			preserved, err := hooks.Install(dir, name, constants.ProgrammName)
+			if err != nil {
+				return err
+			}
+			if preserved {
+				slog.Info("existing hook kept", "hook", name, "file", name+".local")
+			}
+			slog.Info("hook installed", "hook", name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hooksCmd)
+	hooksCmd.AddCommand(hooksInstallCmd)
+	hooksInstallCmd.Flags().BoolVar(&prePushFlag, "pre-push", false, "Also install a pre-push hook")
+}
@@ -1,13 +1,13 @@
-/*
-Copyright © 2024 NAME HERE <EMAIL ADDRESS>
-*/
 package cmd
 
 import (
+	"fmt"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+	"gotver/internal/config"
+	"gotver/internal/exceptions"
 	"gotver/internal/version"
-	"log"
+	"log/slog"
 )
 
 var (
@@ -17,46 +17,48 @@ var (
 // initCmd represents the init command
 var initCmd = &cobra.Command{
 	Use:   "init",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Short: "Initialize gitver for the project",
+	Long: `Initialize gitver for the project: write .gitver/.version with the
+initial version and .gitver/config.yaml with the current schema version.
+Existing files are not overwritten.
+
+Example:
+  gitver config init --version 1.0.0`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		err := version.FromString(versionFlag)
 		if err != nil {
-			log.Fatalf(err.Error())
+			return err
+		}
+
+		// Check both files first, so a failure leaves no half-initialized
+		// project behind.
+		versionFile, file := version.GetFiles()[0], configFile()
+		if exists, _ := afero.Exists(filesystem, versionFile); exists {
+			return version.FileAlreadyExistsError(versionFile)
+		}
+		if exists, _ := afero.Exists(filesystem, file); exists {
+			return fmt.Errorf("%w: configuration file %s", exceptions.ErrAlreadyExists, file)
 		}
 
 		err = version.SafeWriteVersion()
 		if err != nil {
-			log.Fatalf(err.Error())
-			return
+			return err
 		}
 
-		err = viper.SafeWriteConfig()
+		data, err := config.New().Bytes()
 		if err != nil {
-			log.Fatalf(err.Error())
-			return
+			return err
+		}
+		if err := afero.WriteFile(filesystem, file, data, 0o644); err != nil {
+			return fmt.Errorf("%w: %w", exceptions.ErrIO, err)
 		}
 
-		log.Println("Gotver initialized for the project.")
+		slog.Info("Gotver initialized for the project.")
+		return nil
 	},
 }
 
 func init() {
 	configCmd.AddCommand(initCmd)
 	initCmd.Flags().StringVarP(&versionFlag, "version", "v", "0.0.1", "overrides the default initial version")
-
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// initCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// initCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
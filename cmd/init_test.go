@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/spf13/afero"
+	"gotver/internal/exceptions"
+	"gotver/internal/gittest"
+	"gotver/internal/version"
+	"testing"
+)
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		name    string
+		remove  []string
+		wantErr bool
+	}{
+		{"new project", []string{"version", "config"}, false},
+		{"version exists", []string{"config"}, true},
+		{"config exists", []string{"version"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := gittest.New(t).Run("commit feat: initial")
+			fs := setupProject(t, repo, "1.0.0", "0.9.0", testConfig)
+			files := map[string]string{"version": version.GetFiles()[0], "config": configFile()}
+			for _, name := range tt.remove {
+				if err := fs.Remove(files[name]); err != nil {
+					t.Fatalf("remove %s: %v", name, err)
+				}
+			}
+
+			previous := versionFlag
+			versionFlag = "0.1.0"
+			t.Cleanup(func() { versionFlag = previous })
+
+			err := initCmd.RunE(initCmd, nil)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("init error = %v", err)
+				}
+				if got := readFile(t, fs, files["version"]); got != "0.1.0" {
+					t.Errorf("version = %q, want 0.1.0", got)
+				}
+				if got := readFile(t, fs, files["config"]); got != testConfig {
+					t.Errorf("config = %q, want %q", got, testConfig)
+				}
+				return
+			}
+
+			if !errors.Is(err, exceptions.ErrAlreadyExists) {
+				t.Fatalf("init error = %v, want ErrAlreadyExists", err)
+			}
+			for _, name := range tt.remove {
+				if exists, _ := afero.Exists(fs, files[name]); exists {
+					t.Errorf("init wrote the %s file although it failed", name)
+				}
+			}
+		})
+	}
+}
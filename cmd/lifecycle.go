@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/afero"
+	"gotver/internal/gitops"
+	"gotver/internal/lifecycle"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// bumpTransaction runs the lifecycle hooks of a bump and records what the
+// bump changed, so it can be undone when a hook or git operation fails.
+type bumpTransaction struct {
+	runner *lifecycle.Runner
+	files  map[string][]byte
+	head   plumbing.Hash
+	tags   []string
+	pushed bool
+}
+
+func newBumpTransaction() (*bumpTransaction, error) {
+	runner, err := lifecycle.New(projectConfig.Hooks, projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if debugEnabled() {
+		runner.SetOutput(os.Stderr)
+	}
+
+	return &bumpTransaction{runner: runner, files: make(map[string][]byte)}, nil
+}
+
+func (t *bumpTransaction) setVersions(oldVersion, newVersion string) {
+	t.runner.SetEnv(lifecycle.EnvOldVersion, oldVersion)
+	t.runner.SetEnv(lifecycle.EnvNewVersion, newVersion)
+}
+
+func (t *bumpTransaction) setComponent(name string) {
+	t.runner.SetEnv(lifecycle.EnvComponent, name)
+}
+
+func (t *bumpTransaction) setTags(tags ...string) {
+	t.runner.SetEnv(lifecycle.EnvTag, strings.Join(tags, " "))
+}
+
+func (t *bumpTransaction) run(event string) error {
+	slog.Debug("run hooks", "event", event)
+	return t.runner.Run(event)
+}
+
+// snapshot remembers the content of files before they are written. Files
+// that do not exist yet are removed on rollback.
+func (t *bumpTransaction) snapshot(files ...string) {
+	for _, file := range files {
+		if _, ok := t.files[file]; ok {
+			continue
+		}
+
+		data, err := afero.ReadFile(filesystem, file)
+		if err != nil {
+			data = nil
+		}
+		t.files[file] = data
+	}
+}
+
+// recordHead remembers the commit HEAD points to before gitver commits.
+func (t *bumpTransaction) recordHead() error {
+	commit, err := gitops.GetHeadCommit()
+	if err != nil {
+		return err
+	}
+	t.head = commit.Hash
+	return nil
+}
+
+// fail undoes the bump and returns the error that caused it.
+func (t *bumpTransaction) fail(err error) error {
+	t.rollback()
+	return err
+}
+
+func (t *bumpTransaction) rollback() {
+	if t.pushed {
+		slog.Warn("changes are already pushed and cannot be rolled back")
+		return
+	}
+
+	slog.Info("roll back bump")
+	for _, tag := range t.tags {
+		if err := gitops.DeleteTag(tag); err != nil {
+			slog.Error("tag could not be deleted", "tag", tag, "error", err)
+		}
+	}
+
+	if !t.head.IsZero() {
+		if err := gitops.ResetHard(t.head); err != nil {
+			slog.Error("reset failed", "commit", t.head.String(), "error", err)
+		}
+	}
+
+	for file, data := range t.files {
+		var err error
+		if data == nil {
+			err = filesystem.Remove(file)
+		} else {
+			err = afero.WriteFile(filesystem, file, data, os.ModePerm)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			slog.Error("file could not be restored", "file", file, "error", err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"errors"
+	"gotver/internal/constants"
+	"gotver/internal/exceptions"
+	"gotver/internal/gitops"
+	"gotver/internal/gittest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// setupHooks configures a project with the hooks given as YAML. The hooks
+// run in a temporary directory, which is returned.
+func setupHooks(t *testing.T, repo *gittest.Repo, hooks string) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks of the tests are POSIX shell commands")
+	}
+
+	setupProject(t, repo, "1.0.0", "0.9.0", testConfig+"hooks:\n"+hooks)
+	projectDir = t.TempDir()
+	return projectDir
+}
+
+func TestBumpTransactionRollback(t *testing.T) {
+	tests := []struct {
+		name  string
+		hooks string
+	}{
+		{"pre-commit fails", "  pre-commit:\n    - exit 1\n"},
+		{"post-tag fails", "  post-tag:\n    - exit 1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := gittest.New(t).Run(`
+				commit feat: initial
+				tag v1.0.0`)
+			setupHooks(t, repo, tt.hooks)
+			setGitFlag(t, CommitTag)
+
+			previousHead := repo.Head()
+			err := executeGitOperations(bumpAndWrite(t))
+			if !errors.Is(err, exceptions.ErrHook) {
+				t.Fatalf("executeGitOperations() error = %v, want %v", err, exceptions.ErrHook)
+			}
+
+			if got := repo.Head(); got != previousHead {
+				t.Errorf("HEAD = %s, want %s", got, previousHead)
+			}
+			if gitops.HasTag("v1.0.1") {
+				t.Error("tag v1.0.1 was not deleted")
+			}
+
+			dir := filepath.Join(testProjectDir, constants.ConfigFolderName)
+			if got := readFile(t, filesystem, filepath.Join(dir, constants.VersionFileName)); got != "1.0.0" {
+				t.Errorf("version file = %q, want %q", got, "1.0.0")
+			}
+			if got := readFile(t, filesystem, filepath.Join(dir, ".lastversion")); got != "0.9.0" {
+				t.Errorf("last version file = %q, want %q", got, "0.9.0")
+			}
+		})
+	}
+}
+
+func TestBumpTransactionEnv(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag v1.0.0`)
+	dir := setupHooks(t, repo, "  post-tag:\n    - echo \"$GITVER_OLD_VERSION $GITVER_NEW_VERSION $GITVER_TAG $GITVER_HOOK\" > env.txt\n")
+	setGitFlag(t, CommitTag)
+
+	if err := executeGitOperations(bumpAndWrite(t)); err != nil {
+		t.Fatalf("executeGitOperations() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "env.txt"))
+	if err != nil {
+		t.Fatalf("read env: %v", err)
+	}
+	if got, want := string(data), "1.0.0 1.0.1 v1.0.1 post-tag\n"; got != want {
+		t.Errorf("hook environment = %q, want %q", got, want)
+	}
+}
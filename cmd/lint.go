@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gotver/internal/analyzer"
+	"gotver/internal/exceptions"
+	"gotver/internal/gitops"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+)
+
+var (
+	lintFileFlag  string
+	lintRangeFlag string
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check commit messages against the Conventional Commits format",
+	Long: `Check commit messages against the Conventional Commits format used by
+bump --auto. The allowed types and scopes are read from commits.types and
+commits.scopes in the configuration.
+
+The message is read from --file, from the commits of --range or from stdin.
+The command exits with 2 if a message violates a rule.
+
+Example:
+  gitver lint --file .git/COMMIT_EDITMSG
+  gitver lint --range v1.2.0..HEAD
+  echo "feat: add x" | gitver lint`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := readOptionalConfig(); err != nil {
+			return err
+		}
+
+		rules, err := loadLintRules()
+		if err != nil {
+			return err
+		}
+
+		messages, err := readLintMessages()
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		problems := 0
+		for _, message := range messages {
+			for _, violation := range analyzer.Lint(analyzer.CleanMessage(message.text), rules) {
+				fmt.Fprintf(out, "%s:%s\n", message.source, violation)
+				problems++
+			}
+		}
+
+		if problems > 0 {
+			fmt.Fprintf(out, "%d problems found in %d messages\n", problems, len(messages))
+			return exitError(exceptions.ExitValidation)
+		}
+		return nil
+	},
+}
+
+type lintMessage struct {
+	source string
+	text   string
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringVar(&lintFileFlag, "file", "", "Read the commit message from the file, - reads stdin")
+	lintCmd.Flags().StringVar(&lintRangeFlag, "range", "", "Lint the commits of a revision range like v1.2.0..HEAD")
+}
+
+// readOptionalConfig reads the configuration if there is one, commands like
+// lint also work with the defaults.
+func readOptionalConfig() error {
+	if err := readUserConfig(); err != nil {
+		return err
+	}
+	if err := viper.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			return configError(err)
+		}
+		slog.Debug("no configuration found, using defaults")
+		return decodeConfig()
+	}
+
+	if err := decodeConfig(); err != nil {
+		return err
+	}
+	return checkSchemaVersion()
+}
+
+func loadLintRules() (analyzer.Rules, error) {
+	commits := projectConfig.Commits
+	rules := analyzer.Rules{
+		Types:           analyzer.DefaultTypes,
+		Scopes:          commits.Scopes,
+		HeaderMaxLength: commits.HeaderMaxLength,
+	}
+
+	if len(commits.Types) > 0 {
+		rules.Types = commits.Types
+	}
+
+	patterns := append(append([]string(nil), analyzer.DefaultIgnore...), commits.Ignore...)
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return analyzer.Rules{}, fmt.Errorf("%w: invalid ignore pattern %q: %w", exceptions.ErrInvalidConfig, pattern, err)
+		}
+		rules.Ignore = append(rules.Ignore, re)
+	}
+
+	return rules, nil
+}
+
+func readLintMessages() ([]lintMessage, error) {
+	switch {
+	case lintFileFlag != "" && lintRangeFlag != "":
+		return nil, usageError("--file and --range cannot be combined")
+	case lintRangeFlag != "":
+		if err := gitops.ReadRepository(); err != nil {
+			return nil, fmt.Errorf("git repository is not initialized: %w", err)
+		}
+
+		commits, err := gitops.GetCommitsInRange(lintRangeFlag)
+		if err != nil {
+			return nil, err
+		}
+
+		messages := make([]lintMessage, 0, len(commits))
+		for _, c := range commits {
+			messages = append(messages, lintMessage{c.Hash.String()[:7], c.Message})
+		}
+		return messages, nil
+	case lintFileFlag != "" && lintFileFlag != "-":
+		data, err := os.ReadFile(lintFileFlag)
+		if err != nil {
+			return nil, err
+		}
+		return []lintMessage{{lintFileFlag, string(data)}}, nil
+	default:
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		return []lintMessage{{"stdin", string(data)}}, nil
+	}
+}
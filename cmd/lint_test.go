@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"gotver/internal/exceptions"
+	"gotver/internal/gittest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		// want is the output, {{file}} is replaced with the message file.
+		want     string
+		wantExit bool
+	}{
+		{"valid", "feat(api): add login\n", "", false},
+		{"ignored", "Merge branch 'feature'\n", "", false},
+		{"violations", "feat(web): add login\nbody\n", "{{file}}:1:6: scope-enum: scope \"web\" is not one of api\n" +
+			"{{file}}:2:1: body-leading-blank: body must be separated from the header by a blank line\n" +
+			"2 problems found in 1 messages\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := gittest.New(t).Run("commit feat: initial")
+			setupProject(t, repo, "1.0.0", "0.9.0", testConfig+"commits:\n  scopes: [api]\n")
+
+			file := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+			if err := os.WriteFile(file, []byte(tt.message), 0o644); err != nil {
+				t.Fatalf("write message: %v", err)
+			}
+			previous := lintFileFlag
+			lintFileFlag = file
+			t.Cleanup(func() { lintFileFlag = previous })
+
+			var out bytes.Buffer
+			lintCmd.SetOut(&out)
+			t.Cleanup(func() { lintCmd.SetOut(nil) })
+
+			err := lintCmd.RunE(lintCmd, nil)
+			var exit exitError
+			if tt.wantExit && (!errors.As(err, &exit) || exitCode(err) != exceptions.ExitValidation) {
+				t.Fatalf("lint error = %v, want exit code %d", err, exceptions.ExitValidation)
+			}
+			if !tt.wantExit && err != nil {
+				t.Fatalf("lint error = %v", err)
+			}
+
+			if want := strings.ReplaceAll(tt.want, "{{file}}", file); out.String() != want {
+				t.Errorf("lint output = %q, want %q", out.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"gotver/internal/gitops"
+	"gotver/internal/version"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+var (
+	logLevelFlag  string
+	logFormatFlag string
+)
+
+// setupLogger configures the default logger from --log-level and
+// --log-format and hands it to the library packages.
+func setupLogger(out io.Writer) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(logLevelFlag)); err != nil {
+		return usageError(fmt.Sprintf("invalid log level %q, use debug, info, warn or error", logLevelFlag))
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch strings.ToLower(logFormatFlag) {
+	case LogFormatText:
+		handler = slog.NewTextHandler(out, options)
+	case LogFormatJSON:
+		handler = slog.NewJSONHandler(out, options)
+	default:
+		return usageError(fmt.Sprintf("invalid log format %q, use text or json", logFormatFlag))
+	}
+
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+	version.SetLogger(logger)
+	gitops.SetLogger(logger)
+	return nil
+}
+
+// debugEnabled reports whether debug messages are logged, e.g. to decide if
+// the output of hooks is shown.
+func debugEnabled() bool {
+	return slog.Default().Enabled(context.Background(), slog.LevelDebug)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"gotver/internal/exceptions"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestSetupLogger(t *testing.T) {
+	previous := slog.Default()
+	previousLevel, previousFormat := logLevelFlag, logFormatFlag
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		logLevelFlag, logFormatFlag = previousLevel, previousFormat
+	})
+
+	tests := []struct {
+		level, format string
+		want          int
+	}{
+		{"debug", LogFormatJSON, exceptions.ExitOK},
+		{"verbose", LogFormatText, exceptions.ExitValidation},
+		{"info", "yaml", exceptions.ExitValidation},
+	}
+
+	for _, tt := range tests {
+		logLevelFlag, logFormatFlag = tt.level, tt.format
+		if got := exceptions.ExitCode(setupLogger(io.Discard)); got != tt.want {
+			t.Errorf("setupLogger() with --log-level %s --log-format %s exits with %d, want %d", tt.level, tt.format, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gotver/internal/constants"
+	"gotver/internal/gitops"
+	"gotver/internal/notes"
+	"gotver/internal/version"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	notesFormatFlag   string
+	notesRangeFlag    string
+	notesOutputFlag   string
+	notesTemplateFlag string
+)
+
+// notesCmd represents the notes command
+var notesCmd = &cobra.Command{
+	Use:   "notes [version]",
+	Short: "Export the release notes of a version",
+	Long: `Export the release notes of a single version as Markdown, JSON or text.
+
+The notes contain the categorised commits between the version tag and the
+tag of the previous version, a version or a release tag. Without a version
+the current version is used; if it is not tagged yet, the commits since the
+previous tag are used. A given version must be tagged. With --range the
+notes are built for a range of existing tags instead.
+
+The built-in templates can be replaced with Go templates configured as
+notes.templates.<format> or given with --template.
+
+Example:
+  gitver notes 1.4.0 --format json
+  gitver notes --range v1.3.0..v1.4.0 --output RELEASE.md`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := loadConfig(); err != nil {
+			return err
+		}
+
+		if err := gitops.ReadRepository(); err != nil {
+			return fmt.Errorf("git repository is not initialized: %w", err)
+		}
+		applyCommitOptions()
+
+		if notesRangeFlag != "" && len(args) > 0 {
+			return usageError("a version and --range cannot be combined")
+		}
+
+		n, err := buildNotes(notesRangeFlag, args)
+		if err != nil {
+			return err
+		}
+
+		custom, err := loadNotesTemplate(notesFormatFlag)
+		if err != nil {
+			return err
+		}
+
+		out, err := notes.Render(n, notesFormatFlag, custom)
+		if err != nil {
+			return err
+		}
+
+		if notesOutputFlag == "" {
+			fmt.Print(out)
+			return nil
+		}
+
+		return afero.WriteFile(filesystem, notesOutputFlag, []byte(out), os.ModePerm)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(notesCmd)
+	notesCmd.Flags().StringVar(&notesFormatFlag, "format", notes.FormatMarkdown, "Output format: markdown, json or text")
+	notesCmd.Flags().StringVar(&notesRangeFlag, "range", "", "Build the notes for a tag range like v1.3.0..v1.4.0")
+	notesCmd.Flags().StringVar(&notesOutputFlag, "output", "", "Write the notes to the file instead of stdout")
+	notesCmd.Flags().StringVar(&notesTemplateFlag, "template", "", "Render the notes with the Go template file")
+}
+
+// buildNotes collects the commits of a tag range like v1.3.0..v1.4.0 or,
+// without a range, of the requested version.
+func buildNotes(tagRange string, args []string) (notes.Notes, error) {
+	var tag, previousTag, current, previous string
+
+	if tagRange != "" {
+		from, to, found := strings.Cut(tagRange, "..")
+		if !found || to == "" {
+			return notes.Notes{}, usageError(fmt.Sprintf("invalid tag range %q", tagRange))
+		}
+		for _, t := range []string{from, to} {
+			if t != "" && !gitops.HasTag(t) {
+				return notes.Notes{}, gitops.TagNotFoundError(t)
+			}
+		}
+		tag, previousTag = to, from
+
+		current = to
+		if parsed, ok := tagVersion(to); ok {
+			current = parsed
+		}
+		previous, _ = tagVersion(from)
+	} else {
+		current = version.ToString()
+		if len(args) > 0 {
+			current = args[0]
+		}
+
+		tag = fmt.Sprintf(constants.VersionTag, current)
+		switch {
+		case gitops.HasTag(tag):
+		case len(args) > 0:
+			return notes.Notes{}, gitops.TagNotFoundError(tag)
+		default:
+			// The notes of the upcoming release.
+			slog.Debug("version is not tagged, using HEAD", "version", current)
+			tag = ""
+		}
+
+		var err error
+		previousTag, previous, err = previousVersionTag(current)
+		if err != nil {
+			return notes.Notes{}, err
+		}
+	}
+
+	slog.Debug("collect commits between tags", "from", tag, "to", previousTag)
+	commits, err := gitops.GetCommitsBetweenTags(tag, previousTag)
+	if err != nil {
+		return notes.Notes{}, err
+	}
+	slog.Debug("commits found", "count", len(commits))
+
+	n := notes.Build(current, commits)
+	n.Tag = tag
+	n.PreviousTag = previousTag
+	n.PreviousVersion = previous
+
+	return n, nil
+}
+
+// previousVersionTag returns the version or release tag of the highest
+// version lower than the given version and that version. The version tag is
+// preferred if both exist, empty strings are returned if there is none.
+func previousVersionTag(current string) (string, string, error) {
+	tags, err := gitops.GetTags()
+	if err != nil {
+		return "", "", err
+	}
+
+	tag := version.PreviousTag(tags, current, constants.VersionTag)
+	previous, _ := version.ParseTag(tag, constants.VersionTag)
+
+	releaseTag := version.PreviousTag(tags, current, constants.ReleaseTag)
+	if release, ok := version.ParseTag(releaseTag, constants.ReleaseTag); ok && (previous == "" || isNewer(release, previous)) {
+		tag, previous = releaseTag, release
+	}
+
+	return tag, previous, nil
+}
+
+// tagVersion returns the version of a version or release tag.
+func tagVersion(tag string) (string, bool) {
+	if parsed, ok := version.ParseTag(tag, constants.VersionTag); ok {
+		return parsed, true
+	}
+	return version.ParseTag(tag, constants.ReleaseTag)
+}
+
+// loadNotesTemplate reads a custom template from --template or from the
+// configuration. An empty string selects the built-in template.
+func loadNotesTemplate(format string) (string, error) {
+	file := notesTemplateFlag
+	if file == "" {
+		file = projectConfig.Notes.Templates[format]
+		if file != "" && !filepath.IsAbs(file) {
+			file = filepath.Join(projectDir, file)
+		}
+	}
+
+	if file == "" {
+		return "", nil
+	}
+
+	data, err := afero.ReadFile(filesystem, file)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
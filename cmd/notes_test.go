@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"gotver/internal/exceptions"
+	"gotver/internal/gittest"
+	"reflect"
+	"testing"
+)
+
+func TestBuildNotes(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag v1.0.0
+		commit feat: login
+		tag v1.1.0
+		commit fix: logout`)
+	setupProject(t, repo, "1.2.0", "1.1.0", testConfig)
+
+	tests := []struct {
+		name     string
+		args     []string
+		tagRange string
+		want     []string
+		wantCode exceptions.Code
+	}{
+		{name: "tagged version", args: []string{"1.1.0"}, want: []string{"login"}},
+		{name: "current version", want: []string{"logout"}},
+		{name: "range", tagRange: "v1.0.0..v1.1.0", want: []string{"login"}},
+		{name: "untagged version", args: []string{"1.0.5"}, wantCode: exceptions.TagNotFound},
+		{name: "unknown range start", tagRange: "v0.9.0..v1.1.0", wantCode: exceptions.TagNotFound},
+		{name: "unknown range end", tagRange: "v1.0.0..v1.3.0", wantCode: exceptions.TagNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := buildNotes(tt.tagRange, tt.args)
+			if tt.wantCode != 0 {
+				if exceptions.CodeOf(err) != tt.wantCode || exceptions.ExitCode(err) != exceptions.ExitGit {
+					t.Errorf("buildNotes() error = %v, want code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildNotes() error = %v", err)
+			}
+
+			var got []string
+			for _, section := range n.Sections {
+				for _, entry := range section.Entries {
+					got = append(got, entry.Description)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildNotes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildNotesReleaseTags(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag r1.0.0
+		commit feat: login
+		tag r1.1.0
+		commit fix: logout`)
+	setupProject(t, repo, "1.2.0", "1.1.0", testConfig)
+
+	tests := []struct {
+		name            string
+		tagRange        string
+		wantPreviousTag string
+		wantPrevious    string
+		wantVersion     string
+	}{
+		{"current version", "", "r1.1.0", "1.1.0", "1.2.0"},
+		{"range", "r1.0.0..r1.1.0", "r1.0.0", "1.0.0", "1.1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := buildNotes(tt.tagRange, nil)
+			if err != nil {
+				t.Fatalf("buildNotes() error = %v", err)
+			}
+			if n.PreviousTag != tt.wantPreviousTag || n.PreviousVersion != tt.wantPrevious || n.Version != tt.wantVersion {
+				t.Errorf("buildNotes() = %s after %s (%s), want %s after %s (%s)",
+					n.Version, n.PreviousVersion, n.PreviousTag, tt.wantVersion, tt.wantPrevious, tt.wantPreviousTag)
+			}
+			if len(n.Sections) != 1 || len(n.Sections[0].Entries) != 1 {
+				t.Errorf("buildNotes() sections = %+v, want the single commit since %s", n.Sections, tt.wantPreviousTag)
+			}
+		})
+	}
+}
+
+func TestPreviousVersionTag(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag v1.0.0
+		tag r1.0.0
+		commit feat: login
+		tag v1.1.0
+		commit feat: logout
+		tag r1.1.5`)
+	setupProject(t, repo, "1.2.0", "1.1.5", testConfig)
+
+	tests := []struct {
+		current      string
+		want         string
+		wantPrevious string
+	}{
+		{"1.2.0", "r1.1.5", "1.1.5"},
+		{"1.1.5", "v1.1.0", "1.1.0"},
+		{"1.1.0", "v1.0.0", "1.0.0"},
+		{"1.0.0", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current, func(t *testing.T) {
+			tag, previous, err := previousVersionTag(tt.current)
+			if err != nil {
+				t.Fatalf("previousVersionTag() error = %v", err)
+			}
+			if tag != tt.want || previous != tt.wantPrevious {
+				t.Errorf("previousVersionTag() = %q (%s), want %q (%s)", tag, previous, tt.want, tt.wantPrevious)
+			}
+		})
+	}
+}
@@ -4,49 +4,145 @@ Copyright © 2024 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"github.com/spf13/cobra"
 	"gotver/internal/constants"
+	"gotver/internal/exceptions"
 	"gotver/internal/gitops"
+	"gotver/internal/notes"
+	"gotver/internal/publish"
 	"gotver/internal/version"
-	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+var (
+	publishFlag bool
 )
 
 // releaseCmd represents the release command
 var releaseCmd = &cobra.Command{
 	Use:   "release",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+	Short: "Tag the current version as release",
+	Long: `Tag the current version as release.
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	Run: func(cmd *cobra.Command, args []string) {
+With --publish the release tag is pushed to origin and a release is created
+on the forge configured under publish. The release notes of the version are
+attached and the configured assets are uploaded. Publishing again updates
+the existing release and skips assets that are already attached.
+
+Example configuration:
+  publish:
+    provider: gitea          # github, gitlab or gitea
+    baseUrl: https://git.example.com
+    repository: owner/name
+    tokenEnv: GITVER_TOKEN
+    assets:
+      - dist/app.tar.gz`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := loadConfig(); err != nil {
+			return err
+		}
 
-		loadConfig()
 		err := prepareGitOperation()
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+
+		if err := applyBranchPolicy(); err != nil {
+			return err
+		}
+
+		tag := fmt.Sprintf(constants.ReleaseTag, version.ToString())
+		if gitops.HasTag(tag) {
+			if !publishFlag {
+				return nil
+			}
+			slog.Debug("tag already exists", "tag", tag)
+		} else {
+			if err := gitops.CreateTag(tag, constants.TagMessage); err != nil {
+				return err
+			}
+			slog.Info("release tagged", "tag", tag)
 		}
 
-		if err := gitops.CreateTag(fmt.Sprintf(constants.ReleaseTag, version.ToString()), constants.TagMessage); err != nil {
-			return
+		if publishFlag {
+			return publishRelease(tag)
 		}
-		log.Printf("Tag: %s tagged", fmt.Sprintf(constants.ReleaseTag, version.ToString()))
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(releaseCmd)
+	releaseCmd.Flags().BoolVar(&publishFlag, "publish", false, "Push the release tag and publish the release on the configured forge")
+}
+
+// publishRelease pushes the release tag and creates the release with the
+// notes of the current version on the configured forge.
+func publishRelease(tag string) error {
+	cfg := projectConfig.Publish
+	tokenEnv := cfg.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = publish.DefaultTokenEnv
+	}
+	token := os.Getenv(tokenEnv)
+	if token == "" {
+		return fmt.Errorf("%w: no API token found in %s", exceptions.ErrInvalidConfig, tokenEnv)
+	}
+
+	publisher, err := publish.New(cfg, token, nil)
+	if err != nil {
+		return err
+	}
+
+	n, err := buildNotes("", nil)
+	if err != nil {
+		return err
+	}
+	n.Tag = tag
+
+	body, err := notes.Render(n, notes.FormatMarkdown, "")
+	if err != nil {
+		return err
+	}
+
+	assets := make([]string, 0, len(cfg.Assets))
+	for _, asset := range cfg.Assets {
+		if !filepath.IsAbs(asset) {
+			asset = filepath.Join(projectDir, asset)
+		}
+		assets = append(assets, asset)
+	}
 
-	// Here you will define your flags and configuration settings.
+	slog.Debug("push tag", "tag", tag)
+	if err := gitops.PushTag(tag); err != nil {
+		return err
+	}
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// releaseCmd.PersistentFlags().String("foo", "", "A help for foo")
+	result, err := publisher.Publish(context.Background(), publish.Release{
+		Tag:        tag,
+		Name:       version.ToString(),
+		Notes:      body,
+		PreRelease: version.GetPreRelease() != "",
+		Assets:     assets,
+	})
+	if err != nil {
+		return err
+	}
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// releaseCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	if result.Created {
+		slog.Info("release created", "tag", tag, "url", result.URL)
+	} else {
+		slog.Info("release updated", "tag", tag, "url", result.URL)
+	}
+	for _, asset := range result.UploadedAssets {
+		slog.Debug("asset uploaded", "asset", asset)
+	}
+	for _, asset := range result.SkippedAssets {
+		slog.Debug("asset already attached", "asset", asset)
+	}
+	return nil
 }
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gotver/internal/exceptions"
+	"gotver/internal/gitops"
+	"gotver/internal/gittest"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRelease(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag v1.0.0`)
+	setupProject(t, repo, "1.0.0", "0.9.0", testConfig)
+
+	if err := releaseCmd.RunE(releaseCmd, nil); err != nil {
+		t.Fatalf("release error = %v", err)
+	}
+	if got := repo.Resolve("r1.0.0"); got != repo.Head() {
+		t.Errorf("tag r1.0.0 = %s, want %s", got, repo.Head())
+	}
+
+	if err := releaseCmd.RunE(releaseCmd, nil); err != nil {
+		t.Errorf("release of a released version error = %v", err)
+	}
+}
+
+func TestReleaseDirtyRepository(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag v1.0.0`)
+	setupProject(t, repo, "1.0.0", "0.9.0", testConfig)
+	repo.WriteFile("untracked.txt", "dirty")
+
+	err := releaseCmd.RunE(releaseCmd, nil)
+	if !errors.Is(err, exceptions.ErrDirtyRepository) {
+		t.Fatalf("release error = %v, want %v", err, exceptions.ErrDirtyRepository)
+	}
+	if gitops.HasTag("r1.0.0") {
+		t.Error("dirty repository was released")
+	}
+}
+
+func TestReleasePublish(t *testing.T) {
+	var created map[string]any
+	forge := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/repos/owner/name/releases/tags/r1.1.0":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/repos/owner/name/releases":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id": 1, "html_url": "https://git.example.com/owner/name/releases/r1.1.0"}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer forge.Close()
+
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag r1.0.0
+		commit feat: release notes
+		commit fix: bug
+		tag v1.1.0`)
+	remote := repo.AddRemote("origin")
+	setupProject(t, repo, "1.1.0", "1.0.0", testConfig+fmt.Sprintf(`
+publish:
+  provider: gitea
+  baseUrl: %s
+  repository: owner/name
+`, forge.URL))
+	t.Setenv("GITVER_TOKEN", "secret")
+
+	previous := publishFlag
+	publishFlag = true
+	t.Cleanup(func() { publishFlag = previous })
+
+	if err := releaseCmd.RunE(releaseCmd, nil); err != nil {
+		t.Fatalf("release error = %v", err)
+	}
+
+	if !remote.HasTag("r1.1.0") {
+		t.Error("release tag was not pushed")
+	}
+	if created == nil {
+		t.Fatal("release was not created")
+	}
+	if created["tag_name"] != "r1.1.0" || created["name"] != "1.1.0" {
+		t.Errorf("release = %v, want tag r1.1.0 and name 1.1.0", created)
+	}
+	for _, subject := range []string{"release notes", "bug"} {
+		if body, _ := created["body"].(string); !strings.Contains(body, subject) {
+			t.Errorf("release notes %q do not contain %q", body, subject)
+		}
+	}
+	if body, _ := created["body"].(string); strings.Contains(body, "initial") {
+		t.Errorf("release notes %q contain the commits of the previous release r1.0.0", body)
+	}
+}
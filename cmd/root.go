@@ -1,64 +1,89 @@
 package cmd
 
 import (
-	"github.com/spf13/viper"
+	"github.com/spf13/afero"
+	"gotver/internal/config"
 	"gotver/internal/constants"
+	"gotver/internal/exceptions"
 	"gotver/internal/gitops"
 	"gotver/internal/version"
-	"log"
 	"os"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	projectDir string
+	// filesystem holds the version, config and updater files.
+	filesystem afero.Fs = afero.NewOsFs()
+)
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   constants.ProgrammName,
-	Short: "A brief description of your application",
-	Long: `A longer description that spans multiple lines and likely contains
-		examples and usage of using your application. For example:
-		
-		Cobra is a CLI library for Go that empowers applications.
-		This application is a tool to generate the needed files
-		to quickly create a Cobra application.`,
+	Short: "Version a project from its git history",
+	Long: `Version a project from its git history: bump the version from
+conventional commits, tag and publish releases, lint commit messages and
+write release notes.
+
+Every configuration key can be overridden without editing files. A key is
+read from the first of these sources that sets it:
+  1. the flags --scheme, --propagation and --set key=value
+  2. environment variables like GITVER_COMMITS_FIRSTPARENT=true, named
+     GITVER_ and the key in upper case with dots replaced by underscores
+  3. the project configuration .gitver/config.yaml
+  4. the user configuration, e.g. ~/.config/gitver/config.yaml
+  5. the defaults
+
+Lists and objects are given in YAML flow syntax or JSON, e.g.
+GITVER_COMMITS_TYPES="[feat, fix]". "gitver config list" shows the effective
+value of every key and its source.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Arguments and flags are valid, errors of the command itself do
+		// not need the usage.
+		cmd.SilenceUsage = true
+		if err := setupLogger(os.Stderr); err != nil {
+			return err
+		}
+		return applySetFlag()
+	},
+	SilenceErrors: true,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
-func Execute() {
-	err := rootCmd.Execute()
-	if err != nil {
-		os.Exit(1)
-	}
+// It returns the process exit code.
+func Execute() int {
+	return exitCode(rootCmd.Execute())
 }
 
 func init() {
-	// Here you will define your flags and configuration settings.
-	// Cobra supports persistent flags, which, if defined here,
-	// will be global for your application.
-
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.gotver.yaml)")
-
-	// Cobra also supports local flags, which will only run
-	// when this action is called directly.
+	version.SetFs(filesystem)
 
-	projectDir, err := version.GetProjectDirectory()
+	var err error
+	projectDir, err = version.GetProjectDirectory()
 	if err != nil {
 		projectDir, err = os.Getwd()
 		if err != nil {
-			log.Fatalf(err.Error())
+			projectDir = "."
 		}
 	}
 
-	viper.SetConfigName(constants.ConfigName)
-	viper.SetConfigType(constants.ConfigType)
-	viper.AddConfigPath(projectDir + "/" + constants.ConfigFolderName)
-	viper.SetDefault("Version", "0.0.0")
-
 	version.SetFilePath(projectDir + "/" + constants.ConfigFolderName)
 	version.SetFileName(constants.VersionFileName)
 
 	gitops.SetRepositoryPath(projectDir)
 
+	rootCmd.PersistentFlags().StringVar(&logLevelFlag, "log-level", "info", "Log level: debug, info, warn or error")
+	rootCmd.PersistentFlags().StringVar(&logFormatFlag, "log-format", LogFormatText, "Log format: text or json")
+	rootCmd.PersistentFlags().IntVar(&noChangeExitCode, "no-change-exit-code", exceptions.ExitNoChange, "Exit code when no new version is required, e.g. 0 to treat it as success")
+	rootCmd.PersistentFlags().StringArrayVar(&setFlag, "set", nil, "Override a configuration key, e.g. --set commits.firstParent=true, repeatable")
+	rootCmd.PersistentFlags().String("scheme", config.Default().Scheme.Name, "Override the versioning scheme, scheme.name")
+	rootCmd.PersistentFlags().String("propagation", config.Default().Propagation, "Override the bump of dependent components, propagation")
+	configureViper(projectDir + "/" + constants.ConfigFolderName)
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError(err.Error())
+	})
+
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
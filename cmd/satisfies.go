@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"gotver/internal/exceptions"
+	"gotver/internal/version"
+)
+
+var (
+	satisfiesVersionFlag string
+)
+
+// satisfiesCmd represents the satisfies command
+var satisfiesCmd = &cobra.Command{
+	Use:   "satisfies <constraint>",
+	Short: "Check whether the version satisfies a constraint",
+	Long: `Check whether the current version, or the version given with --version,
+satisfies a constraint. The command exits with 0 if the constraint is
+satisfied and with 1 otherwise.
+
+Supported operators are >=, <=, <, >, =, !=, ~ and ^, hyphen ranges like
+1.2 - 1.5, wildcards like 1.2.x and alternatives separated by ||.
+
+Example:
+  gitver satisfies ">=2.3 <3"
+  gitver satisfies "^1.4 || ^2" --version 2.0.1`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		constraint, err := version.ParseConstraint(args[0])
+		if err != nil {
+			return err
+		}
+
+		current := satisfiesVersionFlag
+		if current == "" {
+			if err := loadConfig(); err != nil {
+				return err
+			}
+			current = version.ToString()
+		}
+
+		ok, err := constraint.Check(current)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			fmt.Printf("%s does not satisfy %s\n", current, constraint)
+			return exitError(exceptions.ExitFailure)
+		}
+		fmt.Printf("%s satisfies %s\n", current, constraint)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(satisfiesCmd)
+	satisfiesCmd.Flags().StringVar(&satisfiesVersionFlag, "version", "", "Check the given version instead of the current version")
+}
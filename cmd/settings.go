@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/spf13/viper"
+	"gotver/internal/config"
+	"gotver/internal/constants"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	setFlag []string
+	// userConfigFile is the configuration of the user shared by all
+	// projects, "" if there is no user configuration directory.
+	userConfigFile string
+	// userConfig holds the settings of userConfigFile, nil if it does not
+	// exist.
+	userConfig *viper.Viper
+	// setKeys are the keys overridden with --set.
+	setKeys []string
+)
+
+// configFlags are the persistent flags bound to configuration keys by flag
+// name.
+var configFlags = map[string]string{
+	"scheme":      "scheme.name",
+	"propagation": "propagation",
+}
+
+// envKeyReplacer maps keys to the names of environment variables, e.g.
+// commits.firstParent is read from GITVER_COMMITS_FIRSTPARENT.
+var envKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+func init() {
+	if dir, err := os.UserConfigDir(); err == nil {
+		userConfigFile = filepath.Join(dir, constants.ProgrammName, constants.ConfigName+"."+constants.ConfigType)
+	}
+}
+
+// configureViper sets up the sources of the settings read from dir. viper
+// resolves a key from them in the order of precedence: flags, environment
+// variables, the project configuration, the user configuration and the
+// defaults.
+func configureViper(dir string) {
+	viper.SetFs(filesystem)
+	viper.SetConfigName(constants.ConfigName)
+	viper.SetConfigType(constants.ConfigType)
+	viper.AddConfigPath(dir)
+
+	viper.SetEnvPrefix(constants.EnvPrefix)
+	viper.SetEnvKeyReplacer(envKeyReplacer)
+	viper.AutomaticEnv()
+	// AllSettings only includes the environment variables of bound keys.
+	for _, key := range config.Keys() {
+		_ = viper.BindEnv(key)
+	}
+
+	for name, key := range configFlags {
+		_ = viper.BindPFlag(key, rootCmd.PersistentFlags().Lookup(name))
+	}
+}
+
+// applySetFlag overrides the keys given with --set key=value.
+func applySetFlag() error {
+	setKeys = nil
+	for _, setting := range setFlag {
+		name, value, ok := strings.Cut(setting, "=")
+		if !ok {
+			return usageError("invalid --set " + setting + ", use key=value")
+		}
+
+		key, _, err := config.Lookup(name)
+		if err != nil {
+			return err
+		}
+		viper.Set(key, value)
+		setKeys = append(setKeys, key)
+	}
+	return nil
+}
+
+// readUserConfig reads the user configuration. Its settings are the defaults
+// of viper, so every other source overrides them.
+func readUserConfig() error {
+	userConfig = nil
+	if userConfigFile == "" {
+		return nil
+	}
+
+	settings := viper.New()
+	settings.SetFs(filesystem)
+	settings.SetConfigFile(userConfigFile)
+	if err := settings.ReadInConfig(); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return configError(err)
+	}
+
+	for _, key := range settings.AllKeys() {
+		viper.SetDefault(key, settings.Get(key))
+	}
+	userConfig = settings
+	slog.Debug("user configuration read", "file", userConfigFile)
+	return nil
+}
+
+// envName returns the environment variable a key is read from.
+func envName(key string) string {
+	return constants.EnvPrefix + "_" + strings.ToUpper(envKeyReplacer.Replace(key))
+}
+
+// configSource returns the source the effective value of a key comes from.
+func configSource(key string) string {
+	switch {
+	case flagOverrides(key):
+		return config.SourceFlag
+	case envOverrides(key):
+		return config.SourceEnv
+	case viper.InConfig(key):
+		return config.SourceProject
+	case userConfig != nil && userConfig.InConfig(key):
+		return config.SourceUser
+	default:
+		return config.SourceDefault
+	}
+}
+
+func flagOverrides(key string) bool {
+	for _, k := range setKeys {
+		if strings.EqualFold(k, key) || strings.HasPrefix(strings.ToLower(k), strings.ToLower(key)+".") {
+			return true
+		}
+	}
+	for name, k := range configFlags {
+		if strings.EqualFold(k, key) && rootCmd.PersistentFlags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func envOverrides(key string) bool {
+	_, ok := os.LookupEnv(envName(key))
+	return ok
+}
@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+	"gotver/internal/analyzer"
+	"gotver/internal/constants"
+	"gotver/internal/exceptions"
+	"gotver/internal/gitops"
+	"gotver/internal/notes"
+	"gotver/internal/version"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+var (
+	statusFormatFlag string
+)
+
+// otherCommits groups the commits not following Conventional Commits.
+const otherCommits = "other"
+
+// releaseStatus summarises whether the project is ready for a release.
+type releaseStatus struct {
+	Version     string `json:"version"`
+	LastVersion string `json:"lastVersion"`
+	// LatestTag is the newest tag reachable from HEAD, the tag auto bumps
+	// are detected from.
+	LatestTag  string        `json:"latestTag,omitempty"`
+	VersionTag string        `json:"versionTag,omitempty"`
+	ReleaseTag string        `json:"releaseTag,omitempty"`
+	HeadTags   []string      `json:"headTags"`
+	Tagged     bool          `json:"tagged"`
+	Commits    commitSummary `json:"commits"`
+	AutoBump   bumpPreview   `json:"autoBump"`
+	Clean      bool          `json:"clean"`
+	// Changes are the changed files in the format of git status --short.
+	Changes  []string               `json:"changes"`
+	Upstream *gitops.UpstreamStatus `json:"upstream,omitempty"`
+}
+
+// commitSummary counts the commits since the latest tag by type.
+type commitSummary struct {
+	Since    string      `json:"since,omitempty"`
+	Total    int         `json:"total"`
+	Breaking int         `json:"breaking"`
+	Types    []typeCount `json:"types"`
+}
+
+type typeCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// bumpPreview is the bump `bump --auto` would make.
+type bumpPreview struct {
+	Segment string `json:"segment,omitempty"`
+	Version string `json:"version,omitempty"`
+	// Reason explains why no bump would be made.
+	Reason string `json:"reason,omitempty"`
+}
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the project is ready for a release",
+	Long: `Show the current and the last version, the latest version and release
+tags, whether HEAD is tagged, the commits since the latest tag grouped by
+type, the bump "gitver bump --auto" would make, whether the working tree is
+clean and how far the branch is ahead of or behind its upstream branch.
+
+Nothing is changed and the remote is not fetched.
+
+Example:
+  gitver status
+  gitver status --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if statusFormatFlag != notes.FormatText && statusFormatFlag != notes.FormatJSON {
+			return usageError(fmt.Sprintf("invalid format %q, use text or json", statusFormatFlag))
+		}
+
+		if err := loadConfig(); err != nil {
+			return err
+		}
+
+		if err := gitops.ReadRepository(); err != nil {
+			return fmt.Errorf("git repository is not initialized: %w", err)
+		}
+		applyCommitOptions()
+
+		status, err := collectStatus()
+		if err != nil {
+			return err
+		}
+
+		if statusFormatFlag == notes.FormatJSON {
+			data, err := json.MarshalIndent(status, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return nil
+		}
+
+		printStatus(cmd.OutOrStdout(), status)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().StringVar(&statusFormatFlag, "format", notes.FormatText, "Output format: text or json")
+}
+
+// collectStatus gathers the release status of the loaded project.
+func collectStatus() (releaseStatus, error) {
+	status := releaseStatus{
+		Version:     version.ToString(),
+		LastVersion: version.GetLastVersion(),
+	}
+
+	tags, err := gitops.GetTags()
+	if err != nil {
+		return status, err
+	}
+	status.VersionTag = version.LatestTag(tags, constants.VersionTag)
+	status.ReleaseTag = version.LatestTag(tags, constants.ReleaseTag)
+
+	if status.HeadTags, err = gitops.GetHeadTags(); err != nil {
+		return status, err
+	}
+	status.Tagged = len(status.HeadTags) > 0
+
+	if status.LatestTag, err = gitops.GetLastTag(); err != nil {
+		return status, err
+	}
+
+	commits, err := gitops.GetCommits(status.LatestTag)
+	if err != nil {
+		return status, err
+	}
+	status.Commits = summarizeCommits(status.LatestTag, commits)
+
+	if status.AutoBump, err = previewAutoBump(); err != nil {
+		return status, err
+	}
+
+	worktree, err := gitops.GetStatus()
+	if err != nil {
+		return status, err
+	}
+	status.Clean = worktree.IsClean()
+	status.Changes = changedFiles(worktree)
+
+	upstream, err := gitops.GetUpstreamStatus()
+	var detached gitops.DetachedHeadError
+	switch {
+	case errors.As(err, &detached):
+		slog.Debug("HEAD is detached, upstream not compared")
+	case err != nil:
+		return status, err
+	default:
+		status.Upstream = &upstream
+	}
+
+	return status, nil
+}
+
+// summarizeCommits counts the commits by Conventional Commits type.
+// Reverted commits are not counted.
+func summarizeCommits(since string, commits []*object.Commit) commitSummary {
+	summary := commitSummary{Since: since, Types: []typeCount{}}
+
+	counts := make(map[string]int)
+	for _, c := range analyzer.FilterReverted(commits) {
+		summary.Total++
+
+		message, ok := analyzer.ParseMessage(c.Message)
+		if !ok {
+			counts[otherCommits]++
+			continue
+		}
+
+		counts[message.Type]++
+		if message.Breaking {
+			summary.Breaking++
+		}
+	}
+
+	for commitType, count := range counts {
+		summary.Types = append(summary.Types, typeCount{commitType, count})
+	}
+	sort.Slice(summary.Types, func(i, j int) bool {
+		a, b := summary.Types[i], summary.Types[j]
+		if (a.Type == otherCommits) != (b.Type == otherCommits) {
+			return b.Type == otherCommits
+		}
+		return a.Type < b.Type
+	})
+
+	return summary
+}
+
+// previewAutoBump detects the bump of the auto mode without writing the
+// version. The version of the project is bumped in memory only.
+func previewAutoBump() (bumpPreview, error) {
+	if err := applyBranchPolicy(); err != nil {
+		if errors.Is(err, exceptions.ErrNotAllowed) {
+			return bumpPreview{Reason: err.Error()}, nil
+		}
+		return bumpPreview{}, err
+	}
+
+	priority, err := detectAutoPriority()
+	if errors.Is(err, exceptions.ErrNoChange) {
+		return bumpPreview{Reason: err.Error()}, nil
+	}
+	if err != nil {
+		return bumpPreview{}, err
+	}
+
+	segment, bump := priorityBump(priority)
+	preview := bumpPreview{Segment: segment}
+
+	version.SetDeferWrite(true)
+	if err := bump(); err != nil {
+		if errors.Is(err, exceptions.ErrNotAllowed) {
+			preview.Reason = err.Error()
+			return preview, nil
+		}
+		return bumpPreview{}, err
+	}
+	preview.Version = version.ToString()
+
+	return preview, nil
+}
+
+// changedFiles lists the changes of the working tree like git status
+// --short, sorted by path.
+func changedFiles(status git.Status) []string {
+	paths := make([]string, 0, len(status))
+	for path, file := range status {
+		if file.Staging == git.Unmodified && file.Worktree == git.Unmodified {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	changes := make([]string, 0, len(paths))
+	for _, path := range paths {
+		file := status[path]
+		changes = append(changes, fmt.Sprintf("%c%c %s", file.Staging, file.Worktree, path))
+	}
+	return changes
+}
+
+func printStatus(out io.Writer, status releaseStatus) {
+	line := func(label, format string, args ...any) {
+		fmt.Fprintf(out, "%-14s%s\n", label, fmt.Sprintf(format, args...))
+	}
+	orNone := func(value string) string {
+		if value == "" {
+			return "none"
+		}
+		return value
+	}
+
+	line("version", "%s", status.Version)
+	line("last version", "%s", status.LastVersion)
+	line("latest tag", "%s", orNone(status.LatestTag))
+	line("version tag", "%s", orNone(status.VersionTag))
+	line("release tag", "%s", orNone(status.ReleaseTag))
+	if status.Tagged {
+		line("HEAD", "tagged %s", strings.Join(status.HeadTags, ", "))
+	} else {
+		line("HEAD", "not tagged")
+	}
+
+	since := "in total"
+	if status.Commits.Since != "" {
+		since = "since " + status.Commits.Since
+	}
+	line("commits", "%d %s, %d breaking", status.Commits.Total, since, status.Commits.Breaking)
+	for _, t := range status.Commits.Types {
+		line("", "%-10s%d", t.Type, t.Count)
+	}
+
+	switch {
+	case status.AutoBump.Version != "":
+		line("auto bump", "%s -> %s", status.AutoBump.Segment, status.AutoBump.Version)
+	default:
+		line("auto bump", "none, %s", status.AutoBump.Reason)
+	}
+
+	if status.Clean {
+		line("working tree", "clean")
+	} else {
+		line("working tree", "%d changes", len(status.Changes))
+		for _, change := range status.Changes {
+			line("", "%s", change)
+		}
+	}
+
+	switch {
+	case status.Upstream == nil:
+		line("branch", "detached HEAD")
+	case status.Upstream.Upstream == "":
+		line("branch", "%s, no upstream", status.Upstream.Branch)
+	case status.Upstream.Gone:
+		line("branch", "%s, upstream %s is gone", status.Upstream.Branch, status.Upstream.Upstream)
+	default:
+		line("branch", "%s, %d ahead and %d behind %s", status.Upstream.Branch,
+			status.Upstream.Ahead, status.Upstream.Behind, status.Upstream.Upstream)
+	}
+}
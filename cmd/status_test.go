@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"gotver/internal/gittest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCollectStatus(t *testing.T) {
+	repo := gittest.New(t)
+	repo.AddRemote("origin")
+	repo.Run(`
+		commit feat: initial
+		tag r1.0.0
+		commit feat: new
+		tag v1.1.0
+		push origin
+		commit feat: api\n\nBREAKING CHANGE: removed the old api
+		commit fix: bug
+		commit update readme
+		file untracked.txt dirty`)
+	setupProject(t, repo, "1.1.0", "1.0.0", testConfig)
+
+	status, err := collectStatus()
+	if err != nil {
+		t.Fatalf("collectStatus() error = %v", err)
+	}
+
+	want := releaseStatus{
+		Version:     "1.1.0",
+		LastVersion: "1.0.0",
+		LatestTag:   "v1.1.0",
+		VersionTag:  "v1.1.0",
+		ReleaseTag:  "r1.0.0",
+		HeadTags:    []string{},
+		Commits: commitSummary{
+			Since:    "v1.1.0",
+			Total:    3,
+			Breaking: 1,
+			Types:    []typeCount{{"feat", 1}, {"fix", 1}, {otherCommits, 1}},
+		},
+		AutoBump: bumpPreview{Segment: "major", Version: "2.0.0"},
+		Changes:  []string{"?? untracked.txt"},
+	}
+
+	if status.Upstream == nil || status.Upstream.Ahead != 3 || status.Upstream.Behind != 0 {
+		t.Errorf("upstream = %+v, want 3 ahead and 0 behind", status.Upstream)
+	}
+	status.Upstream = nil
+
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("collectStatus() = %+v, want %+v", status, want)
+	}
+}
+
+func TestCollectStatusNoChange(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag r1.0.0`)
+	setupProject(t, repo, "1.0.0", "0.9.0", testConfig)
+
+	status, err := collectStatus()
+	if err != nil {
+		t.Fatalf("collectStatus() error = %v", err)
+	}
+
+	if !status.Tagged || status.AutoBump.Version != "" || status.AutoBump.Reason == "" || !status.Clean {
+		t.Errorf("collectStatus() = %+v, want a clean, tagged HEAD without bump", status)
+	}
+
+	var out bytes.Buffer
+	printStatus(&out, status)
+	for _, line := range []string{"HEAD          tagged r1.0.0", "auto bump     none", "working tree  clean"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("output does not contain %q:\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"gotver/internal/component"
+	"gotver/internal/constants"
+	"gotver/internal/exceptions"
+	"gotver/internal/gitops"
+	"gotver/internal/version"
+	"io"
+	"log/slog"
+	"path/filepath"
+)
+
+// Sources of truth --fix reconciles toward.
+const (
+	SourceVersion = "version"
+	SourceTag     = "tag"
+)
+
+var (
+	verifyFixFlag    bool
+	verifySourceFlag string
+)
+
+// inconsistency is a drift between two places recording a version.
+type inconsistency struct {
+	subject string
+	message string
+	// fix reconciles the drift toward the source of truth, it is nil if
+	// the drift cannot be fixed that way.
+	fix func() error
+}
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check that version files, tags and manifests agree",
+	Long: `Check that .version, .lastversion, the latest version and release tags
+reachable from HEAD, the version files and tags of the components and the
+dependency references in component manifests agree with each other.
+
+Every inconsistency is reported and the command exits with 2 if there is
+any, so it can be used as a CI gate.
+
+With --fix the inconsistencies are reconciled toward the source of truth
+given with --source:
+  version  the version files are right: .lastversion and the manifests
+           are updated, tags ahead of the version files are reported only
+  tag      the tags are right: the version files are set to the latest
+           tags, then .lastversion and the manifests are updated
+Tags are never created or deleted.
+
+Example:
+  gitver verify
+  gitver verify --fix --source tag`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if verifySourceFlag != SourceVersion && verifySourceFlag != SourceTag {
+			return usageError(fmt.Sprintf("invalid source %q, use version or tag", verifySourceFlag))
+		}
+
+		if err := loadConfig(); err != nil {
+			return err
+		}
+
+		if err := gitops.ReadRepository(); err != nil {
+			return fmt.Errorf("git repository is not initialized: %w", err)
+		}
+
+		out := cmd.OutOrStdout()
+		found, err := verifyProject(verifySourceFlag)
+		if err != nil {
+			return err
+		}
+
+		if verifyFixFlag && len(found) > 0 {
+			if err := fixInconsistencies(out, found); err != nil {
+				return err
+			}
+
+			if err := loadConfig(); err != nil {
+				return err
+			}
+			if found, err = verifyProject(verifySourceFlag); err != nil {
+				return err
+			}
+		}
+
+		printInconsistencies(out, found)
+		if len(found) > 0 {
+			return exitError(exceptions.ExitValidation)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().BoolVar(&verifyFixFlag, "fix", false, "Reconcile the inconsistencies toward the source of truth")
+	verifyCmd.Flags().StringVar(&verifySourceFlag, "source", SourceVersion, "Source of truth for --fix: version or tag")
+}
+
+// verifyProject cross-checks the version files, tags and manifests of the
+// loaded project. The fixes of the inconsistencies reconcile toward source.
+func verifyProject(source string) ([]inconsistency, error) {
+	tags, err := gitops.GetReachableTags()
+	if err != nil {
+		return nil, err
+	}
+
+	found := verifyVersion(tags, source)
+
+	components := projectConfig.Components
+	if len(components) == 0 {
+		return found, nil
+	}
+	if err := component.ValidateAll(components); err != nil {
+		return nil, err
+	}
+
+	componentFound, err := verifyComponents(components, tags, source)
+	if err != nil {
+		return nil, err
+	}
+	return append(found, componentFound...), nil
+}
+
+// verifyVersion checks the version files of the project against the
+// version and release tags.
+func verifyVersion(tags []string, source string) []inconsistency {
+	current, last := version.ToString(), version.GetLastVersion()
+	files := relativePaths(version.GetFiles())
+
+	tag, tagVersion := latestVersionTag(tags, constants.VersionTag)
+	if releaseTag, releaseVersion := latestVersionTag(tags, constants.ReleaseTag); isNewer(releaseVersion, tagVersion) {
+		tag, tagVersion = releaseTag, releaseVersion
+	}
+	previous := func(current string) string {
+		previous := previousTagVersion(tags, constants.VersionTag, current, "")
+		if release := previousTagVersion(tags, constants.ReleaseTag, current, ""); isNewer(release, previous) {
+			previous = release
+		}
+		return previous
+	}
+
+	if isNewer(tagVersion, current) {
+		found := inconsistency{
+			subject: files[0],
+			message: fmt.Sprintf("version %s is behind the latest tag %s", current, tag),
+		}
+		if source == SourceTag {
+			found.fix = func() error {
+				return writeVersionFiles(version.FromString, version.SetLastVersion, version.WriteVersionFiles,
+					tagVersion, previous(tagVersion))
+			}
+		}
+		return []inconsistency{found}
+	}
+
+	// The last version is the previous tagged version. If the version is
+	// not tagged yet, it is the latest tagged version.
+	expectedLast := tagVersion
+	if tagVersion == current {
+		expectedLast = previous(current)
+	}
+
+	switch {
+	case expectedLast != "" && last != expectedLast:
+		return []inconsistency{{
+			subject: files[1],
+			message: fmt.Sprintf("last version %s is not the previous tagged version %s", last, expectedLast),
+			fix: func() error {
+				return writeVersionFiles(version.FromString, version.SetLastVersion, version.WriteVersionFiles,
+					current, expectedLast)
+			},
+		}}
+	case isNewer(last, current):
+		return []inconsistency{{
+			subject: files[1],
+			message: fmt.Sprintf("last version %s is ahead of version %s", last, current),
+		}}
+	}
+
+	return nil
+}
+
+// verifyComponents checks the version files of the components against
+// their tags and the dependency references against the versions of the
+// components.
+func verifyComponents(components []component.Component, tags []string, source string) ([]inconsistency, error) {
+	var found []inconsistency
+
+	// versions are the versions of the components after fixing.
+	versions := make(map[string]string, len(components))
+	for _, c := range components {
+		v, err := readComponentVersion(c)
+		if err != nil {
+			return nil, err
+		}
+		versions[c.Name] = v.ToString()
+
+		format, err := c.Tag("%s")
+		if err != nil {
+			return nil, err
+		}
+
+		tag, tagVersion := latestVersionTag(tags, format)
+		if !isNewer(tagVersion, v.ToString()) {
+			continue
+		}
+
+		drift := inconsistency{
+			subject: relativePaths(v.GetFiles())[0],
+			message: fmt.Sprintf("version %s of component %s is behind the latest tag %s", v.ToString(), c.Name, tag),
+		}
+		if source == SourceTag {
+			versions[c.Name] = tagVersion
+			last := previousTagVersion(tags, format, tagVersion, v.GetLastVersion())
+			drift.fix = func() error {
+				return writeVersionFiles(v.FromString, v.SetLastVersion, v.WriteVersionFiles, tagVersion, last)
+			}
+		}
+		found = append(found, drift)
+	}
+
+	for _, c := range components {
+		for _, dep := range c.Dependencies {
+			if dep.File == "" {
+				continue
+			}
+
+			reference, err := dep.ReadReference(filesystem, projectDir)
+			if err != nil {
+				return nil, err
+			}
+
+			expected, ok := versions[dep.Name]
+			if !ok {
+				return nil, component.ComponentNotFoundError(dep.Name)
+			}
+			if reference == expected {
+				continue
+			}
+
+			dep := dep
+			found = append(found, inconsistency{
+				subject: dep.File,
+				message: fmt.Sprintf("reference of component %s to %s is %s, but %s is %s",
+					c.Name, dep.Name, reference, dep.Name, expected),
+				fix: func() error {
+					return dep.UpdateReference(filesystem, projectDir, expected)
+				},
+			})
+		}
+	}
+
+	return found, nil
+}
+
+// latestVersionTag returns the latest tag of the format and its version.
+func latestVersionTag(tags []string, format string) (string, string) {
+	tag := version.LatestTag(tags, format)
+	if tag == "" {
+		return "", ""
+	}
+	tagVersion, _ := version.ParseTag(tag, format)
+	return tag, tagVersion
+}
+
+// previousTagVersion returns the version of the tag preceding current, or
+// fallback if there is none.
+func previousTagVersion(tags []string, format string, current string, fallback string) string {
+	tag := version.PreviousTag(tags, current, format)
+	if tag == "" {
+		return fallback
+	}
+	previous, _ := version.ParseTag(tag, format)
+	return previous
+}
+
+// relativePaths returns the paths relative to the project directory.
+func relativePaths(paths []string) []string {
+	relative := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if rel, err := filepath.Rel(projectDir, path); err == nil {
+			path = rel
+		}
+		relative = append(relative, path)
+	}
+	return relative
+}
+
+// isNewer reports whether version a is higher than version b. Empty or
+// invalid versions are never newer.
+func isNewer(a, b string) bool {
+	if a == "" {
+		return false
+	}
+	result, err := version.Compare(a, b)
+	return err == nil && result > 0
+}
+
+// writeVersionFiles sets the version and the last version and writes both
+// files, the functions are the ones of the project or a component version.
+func writeVersionFiles(set func(string) error, setLast func(string) error, write func() error, current, last string) error {
+	if err := set(current); err != nil {
+		return err
+	}
+	if last != "" {
+		if err := setLast(last); err != nil {
+			return err
+		}
+	}
+	return write()
+}
+
+func fixInconsistencies(out io.Writer, found []inconsistency) error {
+	for _, drift := range found {
+		if drift.fix == nil {
+			continue
+		}
+
+		slog.Debug("fix inconsistency", "file", drift.subject, "source", verifySourceFlag)
+		if err := drift.fix(); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "fixed %s: %s\n", drift.subject, drift.message)
+	}
+	return nil
+}
+
+func printInconsistencies(out io.Writer, found []inconsistency) {
+	if len(found) == 0 {
+		fmt.Fprintf(out, "version %s is consistent\n", version.ToString())
+		return
+	}
+
+	for _, drift := range found {
+		fmt.Fprintf(out, "%s: %s\n", drift.subject, drift.message)
+	}
+	fmt.Fprintf(out, "%d inconsistencies found\n", len(found))
+}
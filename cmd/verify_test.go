@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/spf13/afero"
+	"gotver/internal/gittest"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyProject(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		current string
+		last    string
+		source  string
+		// want and wantFixed are the numbers of inconsistencies before
+		// and after fixing.
+		want, wantFixed int
+		wantCurrent     string
+		wantLast        string
+	}{
+		{
+			name: "consistent",
+			script: `
+				commit feat: initial
+				tag v1.0.0
+				commit fix: bug
+				tag v1.0.1`,
+			current:     "1.0.1",
+			last:        "1.0.0",
+			source:      SourceVersion,
+			wantCurrent: "1.0.1",
+			wantLast:    "1.0.0",
+		},
+		{
+			name: "untagged version",
+			script: `
+				commit feat: initial
+				tag v1.0.0
+				commit feat: new`,
+			current:     "1.1.0",
+			last:        "1.0.0",
+			source:      SourceVersion,
+			wantCurrent: "1.1.0",
+			wantLast:    "1.0.0",
+		},
+		{
+			name: "released without version tag",
+			script: `
+				commit feat: initial
+				tag v1.0.0
+				commit fix: bug
+				tag r1.0.1
+				commit feat: new
+				tag v1.1.0`,
+			current:     "1.1.0",
+			last:        "1.0.1",
+			source:      SourceVersion,
+			wantCurrent: "1.1.0",
+			wantLast:    "1.0.1",
+		},
+		{
+			name: "last version drift",
+			script: `
+				commit feat: initial
+				tag v1.3.0
+				commit fix: bug
+				tag v1.3.2
+				commit feat: new`,
+			current:     "1.4.0",
+			last:        "1.3.0",
+			source:      SourceVersion,
+			want:        1,
+			wantCurrent: "1.4.0",
+			wantLast:    "1.3.2",
+		},
+		{
+			name: "version behind tag, version is right",
+			script: `
+				commit feat: initial
+				tag v1.3.0
+				commit fix: bug
+				tag v1.3.2`,
+			current:     "1.3.0",
+			last:        "1.2.0",
+			source:      SourceVersion,
+			want:        1,
+			wantFixed:   1,
+			wantCurrent: "1.3.0",
+			wantLast:    "1.2.0",
+		},
+		{
+			name: "version behind tag, tag is right",
+			script: `
+				commit feat: initial
+				tag v1.3.0
+				commit fix: bug
+				tag v1.3.2`,
+			current:     "1.3.0",
+			last:        "1.2.0",
+			source:      SourceTag,
+			want:        1,
+			wantCurrent: "1.3.2",
+			wantLast:    "1.3.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := gittest.New(t).Run(tt.script)
+			fs := setupProject(t, repo, tt.current, tt.last, testConfig)
+
+			found, err := verifyProject(tt.source)
+			if err != nil {
+				t.Fatalf("verifyProject() error = %v", err)
+			}
+			if len(found) != tt.want {
+				t.Fatalf("verifyProject() = %+v, want %d inconsistencies", found, tt.want)
+			}
+
+			if err := fixInconsistencies(&bytes.Buffer{}, found); err != nil {
+				t.Fatalf("fixInconsistencies() error = %v", err)
+			}
+			if err := loadConfig(); err != nil {
+				t.Fatalf("load config: %v", err)
+			}
+			if found, err = verifyProject(tt.source); err != nil || len(found) != tt.wantFixed {
+				t.Errorf("verifyProject() after fixing = %+v, %v, want %d inconsistencies", found, err, tt.wantFixed)
+			}
+
+			dir := filepath.Join(testProjectDir, ".gitver")
+			if got := readFile(t, fs, filepath.Join(dir, ".version")); got != tt.wantCurrent {
+				t.Errorf("version file = %q, want %q", got, tt.wantCurrent)
+			}
+			if got := readFile(t, fs, filepath.Join(dir, ".lastversion")); got != tt.wantLast {
+				t.Errorf("last version file = %q, want %q", got, tt.wantLast)
+			}
+		})
+	}
+}
+
+const componentConfig = testConfig + `
+components:
+  - name: lib
+    path: lib
+  - name: app
+    path: app
+    dependencies:
+      - name: lib
+        file: app/pom.xml
+        xpath: ./project/dependencies/dependency/version
+`
+
+const pom = `<project>
+  <dependencies>
+    <dependency>
+      <artifactId>lib</artifactId>
+      <version>%s</version>
+    </dependency>
+  </dependencies>
+</project>
+`
+
+func TestVerifyComponents(t *testing.T) {
+	for _, source := range []string{SourceVersion, SourceTag} {
+		t.Run(source, func(t *testing.T) {
+			repo := gittest.New(t).Run(`
+				commit feat: initial
+				tag v1.0.0
+				tag lib/v1.2.0
+				tag app/v2.0.0`)
+			fs := setupProject(t, repo, "1.0.0", "0.9.0", componentConfig)
+
+			files := map[string]string{
+				"lib/.version": "1.1.0",
+				"app/.version": "2.0.0",
+				"app/pom.xml":  "<project><dependencies><dependency><artifactId>lib</artifactId><version>1.0.0</version></dependency></dependencies></project>",
+			}
+			for name, content := range files {
+				if err := afero.WriteFile(fs, filepath.Join(testProjectDir, name), []byte(content), 0o644); err != nil {
+					t.Fatalf("write %s: %v", name, err)
+				}
+			}
+
+			found, err := verifyProject(source)
+			if err != nil {
+				t.Fatalf("verifyProject() error = %v", err)
+			}
+			if len(found) != 2 {
+				t.Fatalf("verifyProject() = %+v, want 2 inconsistencies", found)
+			}
+
+			if err := fixInconsistencies(&bytes.Buffer{}, found); err != nil {
+				t.Fatalf("fixInconsistencies() error = %v", err)
+			}
+
+			// Only the tags move lib to the version of its tag, the
+			// reference follows the version of lib either way.
+			wantLib, wantRemaining := "1.1.0", 1
+			if source == SourceTag {
+				wantLib, wantRemaining = "1.2.0", 0
+			}
+
+			if got := readFile(t, fs, filepath.Join(testProjectDir, "lib/.version")); got != wantLib {
+				t.Errorf("lib version = %q, want %q", got, wantLib)
+			}
+			if found, err = verifyProject(source); err != nil || len(found) != wantRemaining {
+				t.Errorf("verifyProject() after fixing = %+v, %v, want %d inconsistencies", found, err, wantRemaining)
+			}
+
+			got := readFile(t, fs, filepath.Join(testProjectDir, "app/pom.xml"))
+			if want := fmt.Sprintf(pom, wantLib); got != want {
+				t.Errorf("pom.xml = %q, want %q", got, want)
+			}
+		})
+	}
+}
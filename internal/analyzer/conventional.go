@@ -0,0 +1,42 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultTypes are the commit types of the Conventional Commits
+// specification and the Angular convention.
+var DefaultTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+var headerPattern = regexp.MustCompile(`^([A-Za-z][\w-]*)(?:\(([^()]*)\))?(!)?: (.*)$`)
+
+var breakingFooterPattern = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+// Message is a commit message parsed according to Conventional Commits.
+type Message struct {
+	Header      string
+	Type        string
+	Scope       string
+	Description string
+	Body        string
+	Breaking    bool
+}
+
+// ParseMessage parses a commit message. The second return value is false
+// if the header does not follow the `type(scope)!: description` format.
+func ParseMessage(message string) (Message, bool) {
+	header, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	m := Message{Header: header, Body: strings.TrimSpace(body)}
+
+	match := headerPattern.FindStringSubmatch(header)
+	if match == nil {
+		return m, false
+	}
+
+	m.Type = match[1]
+	m.Scope = match[2]
+	m.Breaking = match[3] == "!" || breakingFooterPattern.MatchString(m.Body)
+	m.Description = match[4]
+	return m, true
+}
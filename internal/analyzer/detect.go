@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"fmt"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"gotver/internal/constants"
+	"gotver/internal/version"
+	"log/slog"
+)
+
+// History is the part of a git repository the bump detection reads.
+type History interface {
+	GetLatestTag() (string, error)
+	GetCommitsBetweenTags(startTag, endTag string) ([]*object.Commit, error)
+}
+
+// Detection is the bump priority detected from the commits since a tag.
+type Detection struct {
+	Priority int
+	// Tag is the latest tag reachable from HEAD, "" if there is none.
+	Tag string
+	// Commits is the number of commits since Tag.
+	Commits int
+}
+
+// DetectPriority detects the bump priority of the commits since the latest
+// tag for the version current released after last. If the latest tag is
+// the release tag of current or there is no tag, all commits since count.
+// If current is tagged but not released, it is only bumped again if the new
+// commits require a higher bump than the tagged ones. Any other tag requires
+// no bump.
+func DetectPriority(history History, current, last string, logger *slog.Logger) (Detection, error) {
+	tag, err := history.GetLatestTag()
+	if err != nil {
+		return Detection{}, err
+	}
+	detection := Detection{Tag: tag}
+
+	switch tag {
+	case "", fmt.Sprintf(constants.ReleaseTag, current):
+		logger.Debug("analyze commits from head", "tag", tag)
+		commits, err := history.GetCommitsBetweenTags("", tag)
+		if err != nil {
+			return Detection{}, err
+		}
+		detection.Priority, detection.Commits = CommitPriority(commits, logger), len(commits)
+	case fmt.Sprintf(constants.VersionTag, current):
+		released := fmt.Sprintf(constants.ReleaseTag, last)
+		logger.Debug("analyze commits between tags", "from", tag, "to", released)
+		tagged, err := history.GetCommitsBetweenTags(tag, released)
+		if err != nil {
+			return Detection{}, err
+		}
+		commits, err := history.GetCommitsBetweenTags("", tag)
+		if err != nil {
+			return Detection{}, err
+		}
+		if next := CommitPriority(commits, logger); next > CommitPriority(tagged, logger) {
+			detection.Priority = next
+		}
+		detection.Commits = len(commits)
+	}
+
+	logger.Debug("bump priority detected", "tag", tag, "commits", detection.Commits, "priority", detection.Priority)
+	return detection, nil
+}
+
+// Level returns the level a priority bumps: version.SegmentMajor,
+// SegmentMinor or SegmentPatch, "" for PriorityNone.
+func Level(priority int) string {
+	switch priority {
+	case PriorityBreakingChange:
+		return version.SegmentMajor
+	case PriorityFeat:
+		return version.SegmentMinor
+	case PriorityFix:
+		return version.SegmentPatch
+	default:
+		return ""
+	}
+}
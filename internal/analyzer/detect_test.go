@@ -0,0 +1,75 @@
+package analyzer
+
+import (
+	"gotver/internal/gitops"
+	"gotver/internal/gittest"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestDetectPriority(t *testing.T) {
+	tests := []struct {
+		name        string
+		script      string
+		current     string
+		last        string
+		want        int
+		wantCommits int
+	}{
+		{
+			name:        "untagged",
+			script:      "commit fix: a\ncommit feat(api): b",
+			current:     "0.1.0",
+			want:        PriorityFeat,
+			wantCommits: 2,
+		},
+		{
+			name:        "since release",
+			script:      "commit feat: a\ntag r1.0.0\ncommit fix(cli): b",
+			current:     "1.0.0",
+			last:        "0.9.0",
+			want:        PriorityFix,
+			wantCommits: 1,
+		},
+		{
+			name:        "tagged with a higher bump",
+			script:      "commit feat: a\ntag r1.0.0\ncommit fix: b\ntag v1.0.1\ncommit feat!: c",
+			current:     "1.0.1",
+			last:        "1.0.0",
+			want:        PriorityBreakingChange,
+			wantCommits: 1,
+		},
+		{
+			name:        "tagged with the same bump",
+			script:      "commit feat: a\ntag r1.0.0\ncommit fix: b\ntag v1.0.1\ncommit fix: c",
+			current:     "1.0.1",
+			last:        "1.0.0",
+			want:        PriorityNone,
+			wantCommits: 1,
+		},
+		{
+			name:    "other tag",
+			script:  "commit feat: a\ntag v2.0.0\ncommit feat: b",
+			current: "1.0.0",
+			want:    PriorityNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gitops.New()
+			if err := g.SetRepository(gittest.New(t).Run(tt.script).Repository); err != nil {
+				t.Fatalf("set repository: %v", err)
+			}
+
+			got, err := DetectPriority(g, tt.current, tt.last, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			if err != nil {
+				t.Fatalf("DetectPriority() error = %v", err)
+			}
+			if got.Priority != tt.want || got.Commits != tt.wantCommits {
+				t.Errorf("DetectPriority() = %+v, want priority %d after %d commits", got, tt.want, tt.wantCommits)
+			}
+		})
+	}
+}
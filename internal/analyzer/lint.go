@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	RuleHeaderFormat    = "header-format"
+	RuleHeaderLength    = "header-length"
+	RuleTypeEnum        = "type-enum"
+	RuleScopeEnum       = "scope-enum"
+	RuleDescription     = "description-empty"
+	RuleBodyLeadingLine = "body-leading-blank"
+	RuleBreakingFooter  = "breaking-footer-format"
+
+	DefaultHeaderMaxLength = 100
+)
+
+// DefaultIgnore matches messages generated by git and gitver itself.
+var DefaultIgnore = []string{`^Merge `, `^Revert "`, `^Bump Version `}
+
+var scissorsLine = "# ------------------------ >8 ------------------------"
+
+var malformedBreakingFooter = regexp.MustCompile(`(?mi)^breaking[ -]?changes?\s*:?`)
+
+// Rules configures the commit message linter.
+type Rules struct {
+	// Types are the allowed commit types.
+	Types []string
+	// Scopes are the allowed scopes, an empty list allows every scope.
+	Scopes []string
+	// HeaderMaxLength is the maximal header length, zero disables the check.
+	HeaderMaxLength int
+	// Ignore are patterns of messages that are not linted.
+	Ignore []*regexp.Regexp
+}
+
+// Violation is a rule violation at a 1-based line and column of the
+// message.
+type Violation struct {
+	Line    int
+	Column  int
+	Rule    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", v.Line, v.Column, v.Rule, v.Message)
+}
+
+// CleanMessage removes comment lines and everything below the scissors
+// line, like git does before committing.
+func CleanMessage(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if line == scissorsLine {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// Lint checks a cleaned commit message against the rules.
+func Lint(message string, rules Rules) []Violation {
+	for _, ignore := range rules.Ignore {
+		if ignore.MatchString(message) {
+			return nil
+		}
+	}
+
+	lines := strings.Split(message, "\n")
+	header := lines[0]
+
+	var violations []Violation
+	if rules.HeaderMaxLength > 0 && utf8.RuneCountInString(header) > rules.HeaderMaxLength {
+		violations = append(violations, Violation{1, rules.HeaderMaxLength + 1, RuleHeaderLength,
+			fmt.Sprintf("header is longer than %d characters", rules.HeaderMaxLength)})
+	}
+
+	match := headerPattern.FindStringSubmatchIndex(header)
+	if match == nil {
+		violations = append(violations, Violation{1, 1, RuleHeaderFormat,
+			"header must match \"type(scope): description\""})
+		return violations
+	}
+
+	commitType := header[match[2]:match[3]]
+	if !contains(rules.Types, commitType) {
+		violations = append(violations, Violation{1, match[2] + 1, RuleTypeEnum,
+			fmt.Sprintf("type %q is not one of %s", commitType, strings.Join(rules.Types, ", "))})
+	}
+
+	if match[4] >= 0 && len(rules.Scopes) > 0 {
+		for i, scope := range strings.Split(header[match[4]:match[5]], ",") {
+			scope = strings.TrimSpace(scope)
+			if !contains(rules.Scopes, scope) {
+				column := match[4] + 1
+				if i > 0 {
+					column += strings.Index(header[match[4]:match[5]], scope)
+				}
+				violations = append(violations, Violation{1, column, RuleScopeEnum,
+					fmt.Sprintf("scope %q is not one of %s", scope, strings.Join(rules.Scopes, ", "))})
+			}
+		}
+	}
+
+	if strings.TrimSpace(header[match[8]:match[9]]) == "" {
+		violations = append(violations, Violation{1, match[8] + 1, RuleDescription, "description must not be empty"})
+	}
+
+	if len(lines) > 1 && lines[1] != "" {
+		violations = append(violations, Violation{2, 1, RuleBodyLeadingLine, "body must be separated from the header by a blank line"})
+	}
+
+	for i, line := range lines[1:] {
+		if malformedBreakingFooter.MatchString(line) && !breakingFooterPattern.MatchString(line) {
+			violations = append(violations, Violation{i + 2, 1, RuleBreakingFooter,
+				"breaking changes must be declared as \"BREAKING CHANGE: description\""})
+		}
+	}
+
+	return violations
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	rules := Rules{
+		Types:           DefaultTypes,
+		Scopes:          []string{"api", "cli"},
+		HeaderMaxLength: 30,
+	}
+	for _, pattern := range DefaultIgnore {
+		rules.Ignore = append(rules.Ignore, regexp.MustCompile(pattern))
+	}
+
+	tests := []struct {
+		name    string
+		message string
+		// want are the violations as line:column: rule.
+		want []string
+	}{
+		{"valid", "feat(api): add login", nil},
+		{"valid with body and footer", "fix: handle empty input\n\nDetails.\n\nBREAKING CHANGE: input is required", nil},
+		{"ignored merge", "Merge branch 'feature'", nil},
+		{"ignored revert", "Revert \"feat: add login\"", nil},
+		{"header format", "add login", []string{"1:1: header-format"}},
+		{"header length", "feat: add login with a very long description", []string{"1:31: header-length"}},
+		{"header length and format", "this header is way too long and has no type", []string{"1:31: header-length", "1:1: header-format"}},
+		{"type", "feature: add login", []string{"1:1: type-enum"}},
+		{"scope", "feat(web): add login", []string{"1:6: scope-enum"}},
+		{"second scope", "feat(api,web): add login", []string{"1:10: scope-enum"}},
+		{"empty description", "feat(api): ", []string{"1:12: description-empty"}},
+		{"body without blank line", "feat: add login\nwith a body", []string{"2:1: body-leading-blank"}},
+		{"breaking footer", "feat: add login\n\nBody.\nBREAKING CHANGES: sessions expire", []string{"4:1: breaking-footer-format"}},
+		{"breaking footer without colon", "feat: add login\n\nbreaking change sessions expire", []string{"3:1: breaking-footer-format"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range Lint(tt.message, rules) {
+				got = append(got, fmt.Sprintf("%d:%d: %s", v.Line, v.Column, v.Rule))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint(%q) = %q, want %q", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintWithoutRules(t *testing.T) {
+	message := "custom(web): " + "a very long description that exceeds the default maximum header length of a hundred"
+	if violations := Lint(message, Rules{Types: []string{"custom"}}); len(violations) != 0 {
+		t.Errorf("Lint() = %v, want no violations without scopes and header length", violations)
+	}
+}
+
+func TestViolationString(t *testing.T) {
+	v := Violation{Line: 2, Column: 1, Rule: RuleBodyLeadingLine, Message: "body must be separated"}
+	if got, want := v.String(), "2:1: body-leading-blank: body must be separated"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestCleanMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{"plain", "feat: add login", "feat: add login"},
+		{"comments", "feat: add login\n# Please enter the commit message\n\nBody.\n# On branch main", "feat: add login\n\nBody."},
+		{"scissors", "feat: add login\n\n" + scissorsLine + "\ndiff --git a/x b/x", "feat: add login"},
+		{"trailing whitespace", "feat: add login  \r\n\n", "feat: add login"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CleanMessage(tt.message); got != tt.want {
+				t.Errorf("CleanMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
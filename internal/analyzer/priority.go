@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"log/slog"
+)
+
+// Bump priorities in ascending order of significance.
+const (
+	PriorityNone = iota
+	PriorityFix
+	PriorityFeat
+	PriorityBreakingChange
+)
+
+// CommitPriority returns the highest bump priority of the commits. Reverted
+// commits and their reverts are not taken into account.
+func CommitPriority(commits []*object.Commit, logger *slog.Logger) int {
+	highestPriority := PriorityNone
+
+	analyzed := FilterReverted(commits)
+	if len(analyzed) != len(commits) {
+		logger.Debug("reverted commits ignored", "count", len(commits)-len(analyzed))
+	}
+
+	for _, commit := range analyzed {
+		priority := MessagePriority(commit.Message)
+		if priority <= highestPriority {
+			continue
+		}
+
+		highestPriority = priority
+		switch priority {
+		case PriorityBreakingChange:
+			logger.Debug("breaking change found", "commit", commit.Hash.String())
+			return PriorityBreakingChange
+		case PriorityFeat:
+			logger.Debug("feature found", "commit", commit.Hash.String())
+		case PriorityFix:
+			logger.Debug("fix found", "commit", commit.Hash.String())
+		}
+	}
+
+	return highestPriority
+}
+
+// MessagePriority returns the bump priority of a commit message. Breaking
+// changes are marked with `!` or a BREAKING CHANGE footer, messages that do
+// not follow Conventional Commits require no bump.
+func MessagePriority(message string) int {
+	m, ok := ParseMessage(message)
+	switch {
+	case !ok:
+		return PriorityNone
+	case m.Breaking:
+		return PriorityBreakingChange
+	case m.Type == "feat":
+		return PriorityFeat
+	case m.Type == "fix":
+		return PriorityFix
+	default:
+		return PriorityNone
+	}
+}
@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestMessagePriority(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    int
+	}{
+		{"feature", "feat: add login", PriorityFeat},
+		{"scoped feature", "feat(api): add login", PriorityFeat},
+		{"fix", "fix: handle empty input", PriorityFix},
+		{"scoped fix", "fix(cli): handle empty input", PriorityFix},
+		{"bang", "feat!: drop v1 endpoints", PriorityBreakingChange},
+		{"scoped bang", "refactor(api)!: rename fields", PriorityBreakingChange},
+		{"footer", "fix: rename option\n\nBREAKING CHANGE: --out is now --output", PriorityBreakingChange},
+		{"footer with dash", "feat: new format\n\nBREAKING-CHANGE: old files are not read", PriorityBreakingChange},
+		{"other type", "docs: explain bump", PriorityNone},
+		{"type in body", "chore: update deps\n\nfeat: is mentioned here", PriorityNone},
+		{"footer of no conventional commit", "update deps\n\nBREAKING CHANGE: nothing", PriorityNone},
+		{"revert", "Revert \"feat: add login\"\n\nThis reverts commit 0123456.", PriorityNone},
+		{"no description", "feat:", PriorityNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MessagePriority(tt.message); got != tt.want {
+				t.Errorf("MessagePriority(%q) = %d, want %d", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitPriority(t *testing.T) {
+	commit := func(hash, message string) *object.Commit {
+		return &object.Commit{Hash: plumbing.NewHash(hash), Message: message}
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name    string
+		commits []*object.Commit
+		want    int
+	}{
+		{"none", nil, PriorityNone},
+		{"highest wins", []*object.Commit{
+			commit("01", "fix: b"),
+			commit("02", "feat(api): a"),
+			commit("03", "docs: c"),
+		}, PriorityFeat},
+		{"breaking", []*object.Commit{
+			commit("01", "fix: b"),
+			commit("02", "feat!: a"),
+		}, PriorityBreakingChange},
+		{"reverted feature", []*object.Commit{
+			commit("02", "Revert \"feat: a\"\n\nThis reverts commit 0100000000000000000000000000000000000000."),
+			commit("01", "feat: a"),
+			commit("03", "fix: b"),
+		}, PriorityFix},
+		{"reverted revert", []*object.Commit{
+			commit("03", "Revert \"Revert \"feat: a\"\"\n\nThis reverts commit 0200000000000000000000000000000000000000."),
+			commit("02", "Revert \"feat: a\"\n\nThis reverts commit 0100000000000000000000000000000000000000."),
+			commit("01", "feat: a"),
+		}, PriorityFeat},
+		{"reverted revert reverted again", []*object.Commit{
+			commit("04", "Revert \"Revert \"Revert \"feat: a\"\"\"\n\nThis reverts commit 0300000000000000000000000000000000000000."),
+			commit("03", "Revert \"Revert \"feat: a\"\"\n\nThis reverts commit 0200000000000000000000000000000000000000."),
+			commit("02", "Revert \"feat: a\"\n\nThis reverts commit 0100000000000000000000000000000000000000."),
+			commit("01", "feat: a"),
+		}, PriorityNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CommitPriority(tt.commits, logger); got != tt.want {
+				t.Errorf("CommitPriority() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
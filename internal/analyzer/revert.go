@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"regexp"
+	"strings"
+)
+
+var revertTrailer = regexp.MustCompile(`This reverts commit ([0-9a-f]{7,40})`)
+
+// RevertedHash returns the (possibly abbreviated) hash referenced by the
+// `This reverts commit <hash>` trailer of a revert commit.
+func RevertedHash(c *object.Commit) (string, bool) {
+	match := revertTrailer.FindStringSubmatch(c.Message)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// FilterReverted removes revert commits together with the commits they
+// revert when both are part of the given commits. Reverts of commits outside
+// the range are kept. The order of the remaining commits is preserved.
+func FilterReverted(commits []*object.Commit) []*object.Commit {
+	excluded := make(map[*object.Commit]bool)
+	// pairs maps the excluded reverts to the commits they revert.
+	pairs := make(map[*object.Commit]*object.Commit)
+	active := make([]*object.Commit, 0, len(commits))
+
+	var apply func(c *object.Commit)
+	apply = func(c *object.Commit) {
+		hash, ok := RevertedHash(c)
+		if !ok {
+			active = append(active, c)
+			return
+		}
+
+		for j, candidate := range active {
+			if strings.HasPrefix(candidate.Hash.String(), hash) {
+				excluded[candidate] = true
+				excluded[c] = true
+				pairs[c] = candidate
+				active = append(active[:j], active[j+1:]...)
+				return
+			}
+		}
+
+		// A revert of an excluded revert restores the commit that revert
+		// reverted.
+		for revert, original := range pairs {
+			if strings.HasPrefix(revert.Hash.String(), hash) {
+				delete(pairs, revert)
+				excluded[c] = true
+				pairs[c] = revert
+				excluded[original] = false
+				apply(original)
+				return
+			}
+		}
+
+		active = append(active, c)
+	}
+
+	// Commits are ordered newest first, pair them in chronological order so
+	// a revert of a revert restores the original commit.
+	for i := len(commits) - 1; i >= 0; i-- {
+		apply(commits[i])
+	}
+
+	filtered := make([]*object.Commit, 0, len(commits))
+	for _, c := range commits {
+		if !excluded[c] {
+			filtered = append(filtered, c)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,124 @@
+package component
+
+import (
+	"bytes"
+	"gotver/internal/constants"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	DefaultTagTemplate = "{{.Name}}/v{{.Version}}"
+)
+
+// Component is an independently versioned part of a monorepo.
+type Component struct {
+	Name         string       `mapstructure:"name" desc:"Name of the component"`
+	Path         string       `mapstructure:"path" desc:"Directory of the component relative to the project"`
+	VersionFile  string       `mapstructure:"versionFile" desc:"Version file relative to the component directory"`
+	TagTemplate  string       `mapstructure:"tagTemplate" desc:"Go template of the component tags, e.g. {{.Name}}/v{{.Version}}"`
+	Dependencies []Dependency `mapstructure:"dependencies" desc:"Components this component depends on"`
+}
+
+type tagData struct {
+	Name    string
+	Version string
+}
+
+// Find returns the component with the given name.
+func Find(components []Component, name string) (Component, error) {
+	for _, c := range components {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	return Component{}, ComponentNotFoundError(name)
+}
+
+// ValidateAll validates every component, checks that names are unique and
+// that the dependencies form no cycle.
+func ValidateAll(components []Component) error {
+	names := make(map[string]bool, len(components))
+	for _, c := range components {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		if names[c.Name] {
+			return DuplicateComponentError(c.Name)
+		}
+		names[c.Name] = true
+	}
+
+	_, err := Sort(components)
+	return err
+}
+
+// Validate checks that the component has a name, a path and a usable tag
+// template.
+func (c Component) Validate() error {
+	if c.Name == "" || c.Path == "" {
+		return InvalidComponentError(c.Name)
+	}
+
+	if _, err := c.Tag("0.0.0"); err != nil {
+		return err
+	}
+
+	for _, dep := range c.Dependencies {
+		if dep.Name == c.Name {
+			return CyclicDependencyError(c.Name + " -> " + c.Name)
+		}
+		if err := dep.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Tag renders the tag template of the component for the given version.
+func (c Component) Tag(version string) (string, error) {
+	text := c.TagTemplate
+	if text == "" {
+		text = DefaultTagTemplate
+	}
+
+	tmpl, err := template.New(c.Name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", TagTemplateError(text)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, tagData{Name: c.Name, Version: version}); err != nil {
+		return "", TagTemplateError(text)
+	}
+
+	return buf.String(), nil
+}
+
+// VersionFilePath returns the path of the version file relative to the
+// project directory. It defaults to a version file in the component path.
+func (c Component) VersionFilePath() string {
+	if c.VersionFile != "" {
+		return filepath.Clean(c.VersionFile)
+	}
+	return filepath.Join(c.Path, constants.VersionFileName)
+}
+
+// Touches reports whether any of the given repository paths lies below the
+// component path.
+func (c Component) Touches(files []string) bool {
+	prefix := strings.TrimSuffix(path.Clean(filepath.ToSlash(c.Path)), "/")
+	if prefix == "." || prefix == "" {
+		return len(files) > 0
+	}
+
+	for _, file := range files {
+		if file == prefix || strings.HasPrefix(file, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package component
+
+import (
+	"github.com/spf13/afero"
+	"gotver/internal/xml"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Dependency references another component and where its version is
+// recorded in the manifests of the depending component.
+type Dependency struct {
+	Name string `mapstructure:"name" desc:"Name of the component depended on"`
+	// File is the manifest holding the reference, relative to the project
+	// directory.
+	File string `mapstructure:"file" desc:"Manifest referencing the version of the component"`
+	// XPath locates the version element in XML manifests like pom.xml.
+	XPath string `mapstructure:"xpath" desc:"XPath of the version element in an XML manifest"`
+	// Pattern is a regular expression whose first capture group is the
+	// version, for all other manifest formats.
+	Pattern string `mapstructure:"pattern" desc:"Regular expression whose first group is the version"`
+}
+
+// Validate checks that the dependency names a component and, if it points
+// to a manifest, how the version is located.
+func (d Dependency) Validate() error {
+	if d.Name == "" {
+		return InvalidDependencyError(d.Name)
+	}
+
+	if d.File == "" {
+		return nil
+	}
+
+	if (d.XPath == "") == (d.Pattern == "") {
+		return InvalidDependencyError(d.Name)
+	}
+
+	if d.Pattern != "" {
+		re, err := regexp.Compile(d.Pattern)
+		if err != nil || re.NumSubexp() < 1 {
+			return InvalidDependencyError(d.Name)
+		}
+	}
+
+	return nil
+}
+
+// ReadReference returns the version of the dependency recorded in the
+// manifest of the depending component. Dependencies without a manifest
+// return an empty version.
+func (d Dependency) ReadReference(fs afero.Fs, projectDir string) (string, error) {
+	if d.File == "" {
+		return "", nil
+	}
+
+	file := filepath.Join(projectDir, d.File)
+	if d.XPath != "" {
+		return xml.GetVersion(fs, file, d.XPath)
+	}
+
+	re, err := regexp.Compile(d.Pattern)
+	if err != nil {
+		return "", InvalidDependencyError(d.Name)
+	}
+
+	data, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return "", ManifestUpdateError{file, err}
+	}
+
+	matches := re.FindSubmatch(data)
+	if matches == nil {
+		return "", ReferenceNotFoundError(file)
+	}
+	return string(matches[1]), nil
+}
+
+// UpdateReference writes the new version of the dependency into the
+// manifest of the depending component. Dependencies without a manifest are
+// left untouched.
+func (d Dependency) UpdateReference(fs afero.Fs, projectDir string, version string) error {
+	if d.File == "" {
+		return nil
+	}
+
+	file := filepath.Join(projectDir, d.File)
+	if d.XPath != "" {
+		return xml.SetVersion(fs, file, d.XPath, version)
+	}
+
+	re, err := regexp.Compile(d.Pattern)
+	if err != nil {
+		return InvalidDependencyError(d.Name)
+	}
+
+	data, err := afero.ReadFile(fs, file)
+	if err != nil {
+		return ManifestUpdateError{file, err}
+	}
+
+	matches := re.FindSubmatchIndex(data)
+	if matches == nil || matches[2] < 0 {
+		return ReferenceNotFoundError(file)
+	}
+
+	updated := make([]byte, 0, len(data)+len(version))
+	updated = append(updated, data[:matches[2]]...)
+	updated = append(updated, version...)
+	updated = append(updated, data[matches[3]:]...)
+
+	if err := afero.WriteFile(fs, file, updated, os.ModePerm); err != nil {
+		return ManifestUpdateError{file, err}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package component
+
+import (
+	"github.com/spf13/afero"
+	"gotver/internal/exceptions"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const projectDir = "/project"
+
+// manifests are the fixtures of the reference tests, relative to projectDir.
+var manifests = map[string]string{
+	"app/package.json": `{
+  "name": "app",
+  "dependencies": {
+    "lib": "1.2.0"
+  }
+}
+`,
+	"app/go.mod": "module app\n\nrequire example.com/lib v1.2.0\n",
+	"app/pom.xml": `<project>
+  <dependencies>
+    <dependency>
+      <artifactId>lib</artifactId>
+      <version>1.2.0</version>
+    </dependency>
+  </dependencies>
+</project>
+`,
+}
+
+// manifestFs returns a memory filesystem holding the manifests.
+func manifestFs(t *testing.T) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	for name, content := range manifests {
+		if err := afero.WriteFile(fs, filepath.Join(projectDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return fs
+}
+
+var references = []struct {
+	name string
+	dep  Dependency
+	// want is the manifest line holding the updated version.
+	want string
+}{
+	{"json pattern", Dependency{Name: "lib", File: "app/package.json", Pattern: `"lib": "([^"]+)"`}, `"lib": "2.0.0"`},
+	{"text pattern", Dependency{Name: "lib", File: "app/go.mod", Pattern: `example\.com/lib v(\S+)`}, "example.com/lib v2.0.0"},
+	{"xpath", Dependency{Name: "lib", File: "app/pom.xml", XPath: "project/dependencies/dependency[artifactId='lib']/version"}, "<version>2.0.0</version>"},
+}
+
+func TestReadReference(t *testing.T) {
+	for _, tt := range references {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.dep.ReadReference(manifestFs(t), projectDir)
+			if err != nil {
+				t.Fatalf("ReadReference() error = %v", err)
+			}
+			if got != "1.2.0" {
+				t.Errorf("ReadReference() = %q, want 1.2.0", got)
+			}
+		})
+	}
+}
+
+func TestUpdateReference(t *testing.T) {
+	for _, tt := range references {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := manifestFs(t)
+			if err := tt.dep.UpdateReference(fs, projectDir, "2.0.0"); err != nil {
+				t.Fatalf("UpdateReference() error = %v", err)
+			}
+
+			data, err := afero.ReadFile(fs, filepath.Join(projectDir, tt.dep.File))
+			if err != nil {
+				t.Fatalf("read %s: %v", tt.dep.File, err)
+			}
+			if !strings.Contains(string(data), tt.want) || strings.Contains(string(data), "1.2.0") {
+				t.Errorf("manifest = %q, want it to contain %q", data, tt.want)
+			}
+
+			got, err := tt.dep.ReadReference(fs, projectDir)
+			if err != nil || got != "2.0.0" {
+				t.Errorf("ReadReference() after update = %q, %v, want 2.0.0", got, err)
+			}
+		})
+	}
+}
+
+func TestReferenceErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		dep  Dependency
+		want exceptions.Code
+	}{
+		{"json pattern without match", Dependency{Name: "lib", File: "app/package.json", Pattern: `"core": "([^"]+)"`}, exceptions.ReferenceNotFound},
+		{"text pattern without match", Dependency{Name: "lib", File: "app/go.mod", Pattern: `example\.com/core v(\S+)`}, exceptions.ReferenceNotFound},
+		{"xpath without match", Dependency{Name: "lib", File: "app/pom.xml", XPath: "project/version"}, exceptions.XMLElementNotFound},
+		{"missing manifest", Dependency{Name: "lib", File: "app/Cargo.toml", Pattern: `lib = "([^"]+)"`}, exceptions.ManifestUpdate},
+		{"invalid pattern", Dependency{Name: "lib", File: "app/go.mod", Pattern: `(`}, exceptions.InvalidDependency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := manifestFs(t)
+
+			if _, err := tt.dep.ReadReference(fs, projectDir); exceptions.CodeOf(err) != tt.want {
+				t.Errorf("ReadReference() error = %v, want code %d", err, tt.want)
+			}
+			if err := tt.dep.UpdateReference(fs, projectDir, "2.0.0"); exceptions.CodeOf(err) != tt.want {
+				t.Errorf("UpdateReference() error = %v, want code %d", err, tt.want)
+			}
+
+			for name, content := range manifests {
+				if data, _ := afero.ReadFile(fs, filepath.Join(projectDir, name)); string(data) != content {
+					t.Errorf("%s was changed by the failed update", name)
+				}
+			}
+		})
+	}
+}
+
+func TestReferenceWithoutManifest(t *testing.T) {
+	dep := Dependency{Name: "lib"}
+	fs := afero.NewMemMapFs()
+
+	if got, err := dep.ReadReference(fs, projectDir); got != "" || err != nil {
+		t.Errorf("ReadReference() = %q, %v, want no reference", got, err)
+	}
+	if err := dep.UpdateReference(fs, projectDir, "2.0.0"); err != nil {
+		t.Errorf("UpdateReference() error = %v", err)
+	}
+}
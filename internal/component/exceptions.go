@@ -0,0 +1,121 @@
+package component
+
+import (
+	"fmt"
+	"gotver/internal/exceptions"
+)
+
+type ComponentNotFoundError string
+
+func (p ComponentNotFoundError) Error() string {
+	return fmt.Sprintf("error code: %d - component %q is not configured", exceptions.ComponentNotFound, string(p))
+}
+
+func (p ComponentNotFoundError) Code() exceptions.Code {
+	return exceptions.ComponentNotFound
+}
+
+func (p ComponentNotFoundError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.ComponentNotFound, nil)
+}
+
+type InvalidComponentError string
+
+func (p InvalidComponentError) Error() string {
+	return fmt.Sprintf("error code: %d - component %q needs a name and a path", exceptions.InvalidComponent, string(p))
+}
+
+func (p InvalidComponentError) Code() exceptions.Code {
+	return exceptions.InvalidComponent
+}
+
+func (p InvalidComponentError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.InvalidComponent, nil)
+}
+
+type TagTemplateError string
+
+func (p TagTemplateError) Error() string {
+	return fmt.Sprintf("error code: %d - invalid tag template %q", exceptions.TagTemplate, string(p))
+}
+
+func (p TagTemplateError) Code() exceptions.Code {
+	return exceptions.TagTemplate
+}
+
+func (p TagTemplateError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.TagTemplate, nil)
+}
+
+type CyclicDependencyError string
+
+func (p CyclicDependencyError) Error() string {
+	return fmt.Sprintf("error code: %d - cyclic component dependency: %s", exceptions.CyclicDependency, string(p))
+}
+
+func (p CyclicDependencyError) Code() exceptions.Code {
+	return exceptions.CyclicDependency
+}
+
+func (p CyclicDependencyError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.CyclicDependency, nil)
+}
+
+type InvalidDependencyError string
+
+func (p InvalidDependencyError) Error() string {
+	return fmt.Sprintf("error code: %d - dependency %q needs a name and either an xpath or a pattern with a capture group for its file", exceptions.InvalidDependency, string(p))
+}
+
+func (p InvalidDependencyError) Code() exceptions.Code {
+	return exceptions.InvalidDependency
+}
+
+func (p InvalidDependencyError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.InvalidDependency, nil)
+}
+
+type ReferenceNotFoundError string
+
+func (p ReferenceNotFoundError) Error() string {
+	return fmt.Sprintf("error code: %d - no dependency version reference found in %q", exceptions.ReferenceNotFound, string(p))
+}
+
+func (p ReferenceNotFoundError) Code() exceptions.Code {
+	return exceptions.ReferenceNotFound
+}
+
+func (p ReferenceNotFoundError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.ReferenceNotFound, nil)
+}
+
+type ManifestUpdateError struct {
+	file  string
+	error error
+}
+
+func (p ManifestUpdateError) Error() string {
+	return fmt.Sprintf("error code: %d - manifest %q could not be updated: %v", exceptions.ManifestUpdate, p.file, p.error)
+}
+
+func (p ManifestUpdateError) Code() exceptions.Code {
+	return exceptions.ManifestUpdate
+}
+
+func (p ManifestUpdateError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.ManifestUpdate, p.error)
+}
+
+type DuplicateComponentError string
+
+func (p DuplicateComponentError) Error() string {
+	return fmt.Sprintf("error code: %d - component %q is configured more than once", exceptions.DuplicateComponent, string(p))
+}
+
+func (p DuplicateComponentError) Code() exceptions.Code {
+	return exceptions.DuplicateComponent
+}
+
+func (p DuplicateComponentError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.DuplicateComponent, nil)
+}
@@ -0,0 +1,106 @@
+package component
+
+import (
+	"strings"
+)
+
+// Step is a single component bump of a bump plan.
+type Step struct {
+	Component Component
+	// Level is the bump level, higher values are stronger bumps.
+	Level int
+	// Propagated is set when the level was raised because a dependency
+	// was bumped.
+	Propagated bool
+}
+
+// Sort orders the components so every component comes after its
+// dependencies. Unknown dependencies and cycles are reported as errors.
+func Sort(components []Component) ([]Component, error) {
+	byName := make(map[string]Component, len(components))
+	for _, c := range components {
+		byName[c.Name] = c
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(components))
+	sorted := make([]Component, 0, len(components))
+	var stack []string
+
+	var visit func(c Component) error
+	visit = func(c Component) error {
+		switch state[c.Name] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for i, name := range stack {
+				if name == c.Name {
+					start = i
+				}
+			}
+			return CyclicDependencyError(strings.Join(append(stack[start:], c.Name), " -> "))
+		}
+
+		state[c.Name] = visiting
+		stack = append(stack, c.Name)
+
+		for _, dep := range c.Dependencies {
+			d, ok := byName[dep.Name]
+			if !ok {
+				return ComponentNotFoundError(dep.Name)
+			}
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[c.Name] = visited
+		sorted = append(sorted, c)
+		return nil
+	}
+
+	for _, c := range components {
+		if err := visit(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+// Plan computes the ordered bump plan for the given bump levels. Components
+// depending on a bumped component receive at least the propagated level. A
+// level of zero means no bump.
+func Plan(components []Component, levels map[string]int, propagate int) ([]Step, error) {
+	sorted, err := Sort(components)
+	if err != nil {
+		return nil, err
+	}
+
+	planned := make(map[string]bool, len(sorted))
+	var steps []Step
+	for _, c := range sorted {
+		step := Step{Component: c, Level: levels[c.Name]}
+
+		for _, dep := range c.Dependencies {
+			if planned[dep.Name] && step.Level < propagate {
+				step.Level = propagate
+				step.Propagated = true
+			}
+		}
+
+		if step.Level > 0 {
+			planned[c.Name] = true
+			steps = append(steps, step)
+		}
+	}
+
+	return steps, nil
+}
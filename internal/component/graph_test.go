@@ -0,0 +1,130 @@
+package component
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// component returns a component depending on the named components.
+func component(name string, dependencies ...string) Component {
+	c := Component{Name: name, Path: name}
+	for _, dep := range dependencies {
+		c.Dependencies = append(c.Dependencies, Dependency{Name: dep})
+	}
+	return c
+}
+
+func names(components []Component) []string {
+	var result []string
+	for _, c := range components {
+		result = append(result, c.Name)
+	}
+	return result
+}
+
+func TestSort(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []Component
+		want       []string
+	}{
+		{"independent", []Component{component("a"), component("b")}, []string{"a", "b"}},
+		{"chain", []Component{component("app", "lib"), component("lib", "core"), component("core")}, []string{"core", "lib", "app"}},
+		{"diamond", []Component{
+			component("app", "api", "web"),
+			component("api", "core"),
+			component("web", "core"),
+			component("core"),
+		}, []string{"core", "api", "web", "app"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, err := Sort(tt.components)
+			if err != nil {
+				t.Fatalf("Sort() error = %v", err)
+			}
+			if got := names(sorted); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []Component
+		want       error
+	}{
+		{"cycle", []Component{component("a", "b"), component("b", "a")}, CyclicDependencyError("a -> b -> a")},
+		{"indirect cycle", []Component{component("app", "a"), component("a", "b"), component("b", "c"), component("c", "a")},
+			CyclicDependencyError("a -> b -> c -> a")},
+		{"unknown dependency", []Component{component("app", "lib")}, ComponentNotFoundError("lib")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Sort(tt.components)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Sort() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	components := []Component{
+		component("app", "lib"),
+		component("lib", "core"),
+		component("core"),
+		component("docs"),
+	}
+
+	tests := []struct {
+		name      string
+		levels    map[string]int
+		propagate int
+		want      []Step
+	}{
+		{"nothing changed", nil, 1, nil},
+		{"propagated", map[string]int{"core": 3}, 1, []Step{
+			{Component: components[2], Level: 3},
+			{Component: components[1], Level: 1, Propagated: true},
+			{Component: components[0], Level: 1, Propagated: true},
+		}},
+		{"higher own level", map[string]int{"core": 1, "app": 2}, 1, []Step{
+			{Component: components[2], Level: 1},
+			{Component: components[1], Level: 1, Propagated: true},
+			{Component: components[0], Level: 2},
+		}},
+		{"propagated level", map[string]int{"lib": 1, "app": 1}, 2, []Step{
+			{Component: components[1], Level: 1},
+			{Component: components[0], Level: 2, Propagated: true},
+		}},
+		{"no propagation", map[string]int{"core": 3, "docs": 1}, 0, []Step{
+			{Component: components[2], Level: 3},
+			{Component: components[3], Level: 1},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := Plan(components, tt.levels, tt.propagate)
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			if !reflect.DeepEqual(steps, tt.want) {
+				t.Errorf("Plan() = %+v, want %+v", steps, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanCycle(t *testing.T) {
+	_, err := Plan([]Component{component("a", "b"), component("b", "a")}, map[string]int{"a": 1}, 1)
+	if !errors.Is(err, CyclicDependencyError("a -> b -> a")) {
+		t.Errorf("Plan() error = %v, want a cycle error", err)
+	}
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/yaml.v3"
+	"gotver/internal/analyzer"
+	"gotver/internal/component"
+	"gotver/internal/policy"
+	"gotver/internal/publish"
+	"gotver/internal/version"
+	"strings"
+)
+
+// SchemaVersion is the version of the configuration layout of this gitver.
+// Files without schemaVersion predate versioned layouts.
+const SchemaVersion = 1
+
+// Config is the configuration of a project. The keys are the mapstructure
+// tags, the desc tags describe them in the schema.
+type Config struct {
+	SchemaVersion int `mapstructure:"schemaVersion" desc:"Version of the configuration layout"`
+	// Version is not read, the version of the project is the one of
+	// .gitver/.version.
+	Version     string                `mapstructure:"version" desc:"Deprecated, the version is read from .gitver/.version"`
+	Branches    []policy.Policy       `mapstructure:"branches" desc:"Branch policies, the first matching pattern applies"`
+	Commits     Commits               `mapstructure:"commits" desc:"Commit analysis and linting"`
+	Components  []component.Component `mapstructure:"components" desc:"Independently versioned components"`
+	Propagation string                `mapstructure:"propagation" desc:"Bump of components depending on a bumped component"`
+	Scheme      Scheme                `mapstructure:"scheme" desc:"Versioning scheme"`
+	Hooks       map[string][]string   `mapstructure:"hooks" desc:"Commands run on lifecycle events"`
+	Notes       Notes                 `mapstructure:"notes" desc:"Release notes"`
+	Publish     publish.Config        `mapstructure:"publish" desc:"Publishing of releases to a forge"`
+}
+
+// Commits configures how commits are analyzed and linted.
+type Commits struct {
+	FirstParent     bool     `mapstructure:"firstParent" desc:"Follow only the first parent of merge commits"`
+	MergesOnly      bool     `mapstructure:"mergesOnly" desc:"Analyze only merge commits"`
+	Types           []string `mapstructure:"types" desc:"Allowed commit types"`
+	Scopes          []string `mapstructure:"scopes" desc:"Allowed commit scopes, every scope if empty"`
+	HeaderMaxLength int      `mapstructure:"headerMaxLength" desc:"Maximal header length, 0 disables the check"`
+	Ignore          []string `mapstructure:"ignore" desc:"Patterns of commit messages that are not linted"`
+}
+
+// Scheme configures the versioning scheme.
+type Scheme struct {
+	Name     string            `mapstructure:"name" desc:"Name of the scheme"`
+	Format   string            `mapstructure:"format" desc:"Format of calver versions, e.g. YYYY.0M.MICRO"`
+	Segments []version.Segment `mapstructure:"segments" desc:"Segments of custom versions"`
+}
+
+// Notes configures the release notes.
+type Notes struct {
+	Templates map[string]string `mapstructure:"templates" desc:"Custom templates by format"`
+}
+
+// Default returns the configuration of a project without configuration
+// file.
+func Default() Config {
+	return Config{
+		Commits: Commits{
+			Types:           analyzer.DefaultTypes,
+			HeaderMaxLength: analyzer.DefaultHeaderMaxLength,
+		},
+		Propagation: version.SegmentPatch,
+		Scheme:      Scheme{Name: version.SchemeSemVer},
+		Publish:     publish.Config{TokenEnv: publish.DefaultTokenEnv},
+	}
+}
+
+// Decode decodes settings as returned by viper's AllSettings strictly:
+// unknown keys are errors. Keys that are not set keep their defaults.
+func Decode(settings map[string]any) (Config, error) {
+	settings = withoutNulls(expandStrings(settings, Root).(map[string]any))
+	if err := checkKeys(settings, Root, ""); err != nil {
+		return Config{}, err
+	}
+
+	c := Default()
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           &c,
+		WeaklyTypedInput: true,
+		// Configured lists and maps replace the defaults instead of being
+		// merged into them.
+		ZeroFields: true,
+		DecodeHook: mapstructure.StringToSliceHookFunc(","),
+	})
+	if err != nil {
+		return Config{}, err
+	}
+
+	if err := decoder.Decode(settings); err != nil {
+		var decodeErr *mapstructure.Error
+		if errors.As(err, &decodeErr) {
+			return Config{}, InvalidValueError{Message: strings.Join(decodeErr.Errors, "; ")}
+		}
+		return Config{}, InvalidValueError{Message: err.Error()}
+	}
+	return c, nil
+}
+
+// checkKeys reports the first key of value that is not part of the schema.
+// Mismatching types are left to the decoder.
+func checkKeys(value any, schema *Schema, key string) error {
+	switch value := value.(type) {
+	case map[string]any:
+		if schema.Kind != KindObject && schema.Kind != KindMap {
+			return nil
+		}
+		for _, name := range sortedKeys(value) {
+			if schema.Kind == KindMap {
+				if len(schema.Keys) > 0 && !contains(schema.Keys, name) {
+					return UnknownKeyError{Key: join(key, name), Suggestion: suggestKey(key, name, schema.Keys)}
+				}
+				if err := checkKeys(value[name], schema.Items, join(key, name)); err != nil {
+					return err
+				}
+				continue
+			}
+
+			f, ok := schema.Field(name)
+			if !ok {
+				return UnknownKeyError{Key: join(key, name), Suggestion: suggestKey(key, name, schema.names())}
+			}
+			if err := checkKeys(value[name], f.Schema, join(key, f.Name)); err != nil {
+				return err
+			}
+		}
+
+	case []any:
+		if schema.Kind != KindList {
+			return nil
+		}
+		for i, item := range value {
+			if err := checkKeys(item, schema.Items, fmt.Sprintf("%s[%d]", key, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// expandStrings decodes strings holding YAML flow lists or objects, JSON
+// included, where the schema expects a list, a map or an object. Environment
+// variables and flags can only carry strings, e.g.
+// GITVER_BRANCHES='[{pattern: main, type: release}]'.
+func expandStrings(value any, schema *Schema) any {
+	switch value := value.(type) {
+	case string:
+		trimmed := strings.TrimSpace(value)
+		if schema.Kind == KindString || schema.Kind == KindBool || schema.Kind == KindInt ||
+			!strings.HasPrefix(trimmed, "[") && !strings.HasPrefix(trimmed, "{") {
+			return value
+		}
+		var decoded any
+		if err := yaml.Unmarshal([]byte(trimmed), &decoded); err != nil {
+			// Left to the decoder, which reports the mismatching type.
+			return value
+		}
+		return expandStrings(decoded, schema)
+
+	case map[string]any:
+		result := make(map[string]any, len(value))
+		for name, item := range value {
+			switch f, ok := schema.Field(name); {
+			case schema.Kind == KindMap:
+				result[name] = expandStrings(item, schema.Items)
+			case schema.Kind == KindObject && ok:
+				result[name] = expandStrings(item, f.Schema)
+			default:
+				result[name] = item
+			}
+		}
+		return result
+
+	case []any:
+		if schema.Kind != KindList {
+			return value
+		}
+		result := make([]any, len(value))
+		for i, item := range value {
+			result[i] = expandStrings(item, schema.Items)
+		}
+		return result
+	}
+	return value
+}
+
+// withoutNulls removes keys without value, they are the same as unset keys.
+func withoutNulls(settings map[string]any) map[string]any {
+	result := make(map[string]any, len(settings))
+	for key, value := range settings {
+		switch value := value.(type) {
+		case nil:
+			continue
+		case map[string]any:
+			result[key] = withoutNulls(value)
+		default:
+			result[key] = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,452 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"gotver/internal/exceptions"
+	"gotver/internal/policy"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			name: "empty",
+			data: "",
+		},
+		{
+			name: "valid",
+			data: `
+version: 1.0.1
+commits:
+  firstParent: true
+  headerMaxLength: 72
+  types: [feat, fix]
+branches:
+  - pattern: release/*
+    type: release
+    maintenance: true
+hooks:
+  pre-bump:
+    - make test
+scheme:
+  name: calver
+  format: YYYY.0M.MICRO
+`,
+		},
+		{
+			name: "keys are case-insensitive",
+			data: "Commits:\n  FirstParent: true\n",
+		},
+		{
+			name: "unknown keys",
+			data: "commits:\n  firstParnet: true\n  skip: true\nhooks:\n  pre-bmp: [make]\nnotes:\n  templates:\n    html: notes.tmpl\n",
+			want: []string{
+				"2:3: commits.firstParnet: unknown key, did you mean firstParent?",
+				"3:3: commits.skip: unknown key",
+				"5:3: hooks.pre-bmp: unknown key, did you mean pre-bump?",
+				"8:5: notes.templates.html: unknown key, use markdown, json, text",
+			},
+		},
+		{
+			name: "wrong types",
+			data: "commits:\n  headerMaxLength: long\n  mergesOnly: yes please\nbranches:\n  pattern: main\n",
+			want: []string{
+				`2:20: commits.headerMaxLength: expected integer, got string "long"`,
+				`3:15: commits.mergesOnly: expected boolean, got string "yes please"`,
+				"5:3: branches: expected list, got object",
+			},
+		},
+		{
+			name: "list items",
+			data: "components:\n  - name: lib\n    path: lib\n    dependencies:\n      - name: app\n        xpth: ./version\n",
+			want: []string{"6:9: components[0].dependencies[0].xpth: unknown key, did you mean xpath?"},
+		},
+		{
+			name: "enum",
+			data: "propagation: all\n",
+			want: []string{`1:14: propagation: invalid value "all", use none, patch, minor, major`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := Validate([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			var got []string
+			for _, p := range problems {
+				got = append(got, p.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSyntax(t *testing.T) {
+	_, err := Validate([]byte("commits: [\n"))
+	if !errors.Is(err, exceptions.ErrInvalidConfig) || exceptions.CodeOf(err) != exceptions.ConfigSyntax {
+		t.Errorf("Validate() error = %v, want a syntax error", err)
+	}
+}
+
+func TestDocumentSet(t *testing.T) {
+	const data = `# gitver configuration
+version: 1.0.1 # initial
+Commits:
+  types: [feat, fix]
+publish:
+  provider: github
+`
+
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		want  string
+	}{
+		{
+			name:  "existing key",
+			key:   "version",
+			value: "1.10",
+			want: `# gitver configuration
+version: "1.10" # initial
+Commits:
+  types: [feat, fix]
+publish:
+  provider: github
+`,
+		},
+		{
+			name:  "new key in existing object",
+			key:   "commits.firstParent",
+			value: "true",
+			want: `# gitver configuration
+version: 1.0.1 # initial
+Commits:
+  types: [feat, fix]
+  firstParent: true
+publish:
+  provider: github
+`,
+		},
+		{
+			name:  "new object",
+			key:   "scheme.segments",
+			value: "[{name: major}, {name: build, keep: true}]",
+			want: `# gitver configuration
+version: 1.0.1 # initial
+Commits:
+  types: [feat, fix]
+publish:
+  provider: github
+scheme:
+  segments:
+    - name: major
+    - name: build
+      keep: true
+`,
+		},
+		{
+			name:  "map value",
+			key:   "hooks.pre-bump",
+			value: "[make test]",
+			want: `# gitver configuration
+version: 1.0.1 # initial
+Commits:
+  types: [feat, fix]
+publish:
+  provider: github
+hooks:
+  pre-bump:
+    - make test
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Parse([]byte(data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if err := d.Set(tt.key, tt.value); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+
+			got, err := d.Bytes()
+			if err != nil {
+				t.Fatalf("Bytes() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Bytes() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocumentSetInvalid(t *testing.T) {
+	tests := []struct {
+		key, value string
+		code       exceptions.Code
+	}{
+		{"commits.firstParnet", "true", exceptions.UnknownKey},
+		{"hooks.pre-bmp", "[make]", exceptions.UnknownKey},
+		{"commits.headerMaxLength", "long", exceptions.InvalidValue},
+		{"scheme.name", "semantic", exceptions.InvalidValue},
+		{"commits.types", "[feat", exceptions.ConfigSyntax},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			d, err := Parse(nil)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if err := d.Set(tt.key, tt.value); exceptions.CodeOf(err) != tt.code {
+				t.Errorf("Set() error = %v, want code %d", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	c, err := Decode(map[string]any{
+		"schemaversion": 1,
+		"commits": map[string]any{
+			"firstparent":     true,
+			"types":           []any{"feat", "fix"},
+			"headermaxlength": 0,
+		},
+		"scheme": nil,
+		"hooks":  map[string]any{"pre-bump": []any{"make test"}},
+		"branches": []any{
+			map[string]any{"pattern": "main", "type": "release"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	want := Default()
+	want.SchemaVersion = 1
+	want.Commits.FirstParent = true
+	want.Commits.Types = []string{"feat", "fix"}
+	want.Commits.HeaderMaxLength = 0
+	want.Hooks = map[string][]string{"pre-bump": {"make test"}}
+	want.Branches = []policy.Policy{{Pattern: "main", Type: "release"}}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("Decode() = %+v, want %+v", c, want)
+	}
+}
+
+func TestDecodeStrings(t *testing.T) {
+	c, err := Decode(map[string]any{
+		"commits": map[string]any{
+			"firstparent": "true",
+			"types":       "feat,fix",
+			"scopes":      "[api, cli]",
+		},
+		"branches": `[{"pattern": "main", "type": "release"}]`,
+		"hooks":    map[string]any{"pre-bump": "[make test]"},
+	})
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	want := Default()
+	want.Commits.FirstParent = true
+	want.Commits.Types = []string{"feat", "fix"}
+	want.Commits.Scopes = []string{"api", "cli"}
+	want.Branches = []policy.Policy{{Pattern: "main", Type: "release"}}
+	want.Hooks = map[string][]string{"pre-bump": {"make test"}}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("Decode() = %+v, want %+v", c, want)
+	}
+
+	_, err = Decode(map[string]any{"branches": "[{pattern: main, typ: release}]"})
+	if exceptions.CodeOf(err) != exceptions.UnknownKey {
+		t.Errorf("Decode() error = %v, want an unknown key", err)
+	}
+}
+
+func TestDecodeStrict(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]any
+		want     string
+	}{
+		{
+			name:     "misspelled key",
+			settings: map[string]any{"commits": map[string]any{"firstparnet": true}},
+			want:     `unknown configuration key "commits.firstparnet", did you mean "commits.firstParent"?`,
+		},
+		{
+			name:     "unknown key",
+			settings: map[string]any{"changelog": true},
+			want:     `unknown configuration key "changelog"`,
+		},
+		{
+			name:     "key of list item",
+			settings: map[string]any{"branches": []any{map[string]any{"pattern": "main", "typ": "release"}}},
+			want:     `unknown configuration key "branches[0].typ", did you mean "branches[0].type"?`,
+		},
+		{
+			name:     "invalid value",
+			settings: map[string]any{"commits": map[string]any{"headermaxlength": "long"}},
+			want:     "invalid configuration value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.settings)
+			if !errors.Is(err, exceptions.ErrInvalidConfig) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Decode() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema() error = %v", err)
+	}
+
+	var schema struct {
+		Schema     string `json:"$schema"`
+		Properties map[string]struct {
+			Type       string         `json:"type"`
+			Properties map[string]any `json:"properties"`
+		} `json:"properties"`
+		AdditionalProperties bool `json:"additionalProperties"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("JSONSchema() is not valid JSON: %v", err)
+	}
+
+	if schema.Schema != JSONSchemaDialect || schema.AdditionalProperties {
+		t.Errorf("JSONSchema() = %s, want a closed schema of draft %s", data, JSONSchemaDialect)
+	}
+	for _, key := range Keys() {
+		name, field, _ := strings.Cut(key, ".")
+		property, ok := schema.Properties[name]
+		if !ok {
+			t.Errorf("JSONSchema() has no property %s", name)
+			continue
+		}
+		if _, ok := property.Properties[field]; field != "" && !ok {
+			t.Errorf("JSONSchema() has no property %s", key)
+		}
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	for i, m := range Migrations {
+		if m.From != i {
+			t.Errorf("migration %d migrates from %d", i, m.From)
+		}
+	}
+	if len(Migrations) != SchemaVersion {
+		t.Errorf("%d migrations lead to schema version %d, want %d", len(Migrations), len(Migrations), SchemaVersion)
+	}
+}
+
+func TestDocumentMigrate(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		want        string
+		wantApplied int
+		wantCode    exceptions.Code
+	}{
+		{
+			name:        "legacy version",
+			data:        "# gitver\nversion: 1.0.1 # legacy\ncommits:\n  firstParent: true\n",
+			want:        "# gitver\nschemaVersion: 1\ncommits:\n  firstParent: true\n",
+			wantApplied: 1,
+		},
+		{
+			name:        "empty",
+			data:        "",
+			want:        "schemaVersion: 1\n",
+			wantApplied: 1,
+		},
+		{
+			name: "current",
+			data: "schemaVersion: 1\nversion: 1.0.1\n",
+			want: "schemaVersion: 1\nversion: 1.0.1\n",
+		},
+		{
+			name:     "newer",
+			data:     "schemaVersion: 99\n",
+			wantCode: exceptions.NewerSchema,
+		},
+		{
+			name:     "invalid",
+			data:     "schemaVersion: one\n",
+			wantCode: exceptions.InvalidValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Parse([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			applied, err := d.Migrate()
+			if tt.wantCode != 0 {
+				if exceptions.CodeOf(err) != tt.wantCode {
+					t.Errorf("Migrate() error = %v, want code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil || len(applied) != tt.wantApplied {
+				t.Fatalf("Migrate() = %d migrations, %v, want %d", len(applied), err, tt.wantApplied)
+			}
+
+			if got, _ := d.Bytes(); string(got) != tt.want {
+				t.Errorf("Bytes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	b := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+	want := `--- old
++++ new
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -10,3 +10,4 @@
+ j
+ k
+ l
++m
+`
+	if got := Diff("old", "new", []byte(a), []byte(b)); got != want {
+		t.Errorf("Diff() =\n%s\nwant\n%s", got, want)
+	}
+	if got := Diff("old", "new", []byte(a), []byte(a)); got != "" {
+		t.Errorf("Diff() of equal files = %q, want none", got)
+	}
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around changes.
+const diffContext = 3
+
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// Diff returns the changes from a to b in unified diff format, "" if there
+// are none.
+func Diff(nameA, nameB string, a, b []byte) string {
+	lines := diffLines(splitLines(a), splitLines(b))
+
+	// positions are the line numbers of a and b before every diff line.
+	type position struct{ a, b int }
+	positions := make([]position, len(lines)+1)
+	for i, line := range lines {
+		positions[i+1] = positions[i]
+		if line.op != '+' {
+			positions[i+1].a++
+		}
+		if line.op != '-' {
+			positions[i+1].b++
+		}
+	}
+
+	var out strings.Builder
+	for start := 0; start < len(lines); {
+		for start < len(lines) && lines[start].op == ' ' {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+
+		// A hunk ends where the changes are further apart than the
+		// context of both.
+		end := start + 1
+		for i := end; i < len(lines) && i-end < 2*diffContext; i++ {
+			if lines[i].op != ' ' {
+				end = i + 1
+			}
+		}
+
+		from, to := max(0, start-diffContext), min(len(lines), end+diffContext)
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(positions[from].a, positions[to].a-positions[from].a),
+			hunkRange(positions[from].b, positions[to].b-positions[from].b))
+		for _, line := range lines[from:to] {
+			fmt.Fprintf(&out, "%c%s\n", line.op, line.text)
+		}
+		start = to
+	}
+	return out.String()
+}
+
+// diffLines returns the lines of a and b marked as unchanged, removed or
+// added, using their longest common subsequence.
+func diffLines(a, b []string) []diffLine {
+	// common[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || common[i+1][j] >= common[i][j+1]):
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+// hunkRange formats the start line and the number of lines of a hunk, the
+// start is the line before the hunk if it is empty.
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+func splitLines(data []byte) []string {
+	text := strings.TrimSuffix(string(data), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
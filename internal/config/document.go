@@ -0,0 +1,189 @@
+package config
+
+import (
+	"bytes"
+	"gopkg.in/yaml.v3"
+	"strconv"
+	"strings"
+)
+
+// Document is a configuration file that single keys can be set in. The
+// order, comments and spelling of all other keys are kept.
+type Document struct {
+	root yaml.Node
+}
+
+// Parse reads a configuration file, an empty file is an empty document.
+func Parse(data []byte) (*Document, error) {
+	d := &Document{}
+	if err := yaml.Unmarshal(data, &d.root); err != nil {
+		return nil, SyntaxError{error: err}
+	}
+	if len(d.root.Content) == 0 {
+		d.root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if node := d.root.Content[0]; node.Kind != yaml.MappingNode {
+		return nil, InvalidValueError{Key: orRoot(""), Message: "expected object, got " + describe(node)}
+	}
+	return d, nil
+}
+
+// SchemaVersion returns the schema version of the file, 0 if it has none.
+func (d *Document) SchemaVersion() (int, error) {
+	node := d.value("schemaVersion")
+	if node == nil || node.Tag == "!!null" {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(node.Value)
+	if err != nil || node.Kind != yaml.ScalarNode {
+		return 0, InvalidValueError{Key: "schemaVersion", Message: "expected integer, got " + describe(node)}
+	}
+	return version, nil
+}
+
+// setSchemaVersion sets the schema version, a new key is added as the first
+// key of the file.
+func (d *Document) setSchemaVersion(version int) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+	if node := d.value("schemaVersion"); node != nil {
+		*node = *value
+		return
+	}
+
+	mapping := d.root.Content[0]
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "schemaVersion"}
+	if len(mapping.Content) > 0 {
+		// The comment above the first key is the comment of the file.
+		key.HeadComment, mapping.Content[0].HeadComment = mapping.Content[0].HeadComment, ""
+	}
+	mapping.Content = append([]*yaml.Node{key, value}, mapping.Content...)
+}
+
+// value returns the value node of a top-level key, nil if it is not set.
+func (d *Document) value(key string) *yaml.Node {
+	mapping := d.root.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if strings.EqualFold(mapping.Content[i].Value, key) {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// remove removes a top-level key. The comment of the file above the first
+// key is kept.
+func (d *Document) remove(key string) {
+	mapping := d.root.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if !strings.EqualFold(mapping.Content[i].Value, key) {
+			continue
+		}
+
+		comment := mapping.Content[i].HeadComment
+		mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+		if i == 0 && comment != "" && len(mapping.Content) > 0 {
+			mapping.Content[i].HeadComment = strings.TrimSpace(comment + "\n" + mapping.Content[i].HeadComment)
+		}
+		return
+	}
+}
+
+// New returns a configuration file of the current schema version.
+func New() *Document {
+	d, _ := Parse(nil)
+	d.root.Content[0].Content = []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "schemaVersion"},
+		{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(SchemaVersion)},
+	}
+	return d
+}
+
+// Set sets a key to a value given in YAML syntax, e.g. `true`, `42` or
+// `[feat, fix]`. Missing parent keys are created. The value is checked
+// against the schema of the key.
+func (d *Document) Set(key, value string) error {
+	key, schema, err := Lookup(key)
+	if err != nil {
+		return err
+	}
+
+	node, err := parseValue(value, schema)
+	if err != nil {
+		return err
+	}
+	if problems := validateNode(node, schema, key); len(problems) > 0 {
+		return InvalidValueError{Key: key, Message: problems[0].Message}
+	}
+
+	parent := d.root.Content[0]
+	names := strings.Split(key, ".")
+	for i, name := range names {
+		if parent.Kind != yaml.MappingNode {
+			*parent = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+
+		index := -1
+		for j := 0; j+1 < len(parent.Content); j += 2 {
+			if strings.EqualFold(parent.Content[j].Value, name) {
+				index = j + 1
+				break
+			}
+		}
+		if index < 0 {
+			parent.Content = append(parent.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name},
+				&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+			index = len(parent.Content) - 1
+		}
+
+		if i == len(names)-1 {
+			node.LineComment = parent.Content[index].LineComment
+			parent.Content[index] = node
+		}
+		parent = parent.Content[index]
+	}
+	return nil
+}
+
+// Bytes returns the document in YAML syntax.
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&d.root); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// parseValue parses a value given in YAML syntax. Scalars of string keys
+// are kept as strings, so 1.10 stays 1.10.
+func parseValue(value string, schema *Schema) (*yaml.Node, error) {
+	if schema.Kind == KindString {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &document); err != nil {
+		return nil, SyntaxError{error: err}
+	}
+	if len(document.Content) == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+
+	node := document.Content[0]
+	blockStyle(node)
+	return node, nil
+}
+
+// blockStyle formats a value given in flow style like the rest of the file.
+func blockStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"gotver/internal/exceptions"
+)
+
+type UnknownKeyError struct {
+	Key string
+	// Suggestion is the supported key closest to Key, if any.
+	Suggestion string
+}
+
+func (p UnknownKeyError) Error() string {
+	if p.Suggestion != "" {
+		return fmt.Sprintf("error code: %d - unknown configuration key %q, did you mean %q?", exceptions.UnknownKey, p.Key, p.Suggestion)
+	}
+	return fmt.Sprintf("error code: %d - unknown configuration key %q", exceptions.UnknownKey, p.Key)
+}
+
+func (p UnknownKeyError) Code() exceptions.Code {
+	return exceptions.UnknownKey
+}
+
+func (p UnknownKeyError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.UnknownKey, nil)
+}
+
+type InvalidValueError struct {
+	Key     string
+	Message string
+}
+
+func (p InvalidValueError) Error() string {
+	if p.Key == "" {
+		return fmt.Sprintf("error code: %d - invalid configuration value: %s", exceptions.InvalidValue, p.Message)
+	}
+	return fmt.Sprintf("error code: %d - invalid value of %s: %s", exceptions.InvalidValue, p.Key, p.Message)
+}
+
+func (p InvalidValueError) Code() exceptions.Code {
+	return exceptions.InvalidValue
+}
+
+func (p InvalidValueError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.InvalidValue, nil)
+}
+
+type SyntaxError struct {
+	error error
+}
+
+func (p SyntaxError) Error() string {
+	return fmt.Sprintf("error code: %d - invalid configuration syntax: %v", exceptions.ConfigSyntax, p.error)
+}
+
+func (p SyntaxError) Code() exceptions.Code {
+	return exceptions.ConfigSyntax
+}
+
+func (p SyntaxError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.ConfigSyntax, p.error)
+}
+
+type NewerSchemaError int
+
+func (p NewerSchemaError) Error() string {
+	return fmt.Sprintf("error code: %d - configuration schema version %d is newer than the supported version %d", exceptions.NewerSchema, int(p), SchemaVersion)
+}
+
+func (p NewerSchemaError) Code() exceptions.Code {
+	return exceptions.NewerSchema
+}
+
+func (p NewerSchemaError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.NewerSchema, nil)
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"encoding/json"
+)
+
+// JSONSchemaDialect is the JSON Schema draft the exported schema follows.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns the schema of the configuration file as JSON Schema,
+// so editors can validate and complete .gitver/config.yaml.
+func JSONSchema() ([]byte, error) {
+	schema := jsonSchema(Root)
+	schema["$schema"] = JSONSchemaDialect
+	schema["title"] = "gitver configuration"
+	schema["description"] = "Configuration of a gitver project in .gitver/config.yaml"
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func jsonSchema(schema *Schema) map[string]any {
+	result := map[string]any{}
+	if schema.Description != "" {
+		result["description"] = schema.Description
+	}
+	if schema.Default != nil {
+		result["default"] = schema.Default
+	}
+
+	switch schema.Kind {
+	case KindObject:
+		properties := make(map[string]any, len(schema.Fields))
+		for _, f := range schema.Fields {
+			properties[f.Name] = jsonSchema(f.Schema)
+		}
+		result["type"] = "object"
+		result["properties"] = properties
+		result["additionalProperties"] = false
+	case KindMap:
+		result["type"] = "object"
+		result["additionalProperties"] = jsonSchema(schema.Items)
+		if len(schema.Keys) > 0 {
+			result["propertyNames"] = map[string]any{"enum": schema.Keys}
+		}
+	case KindList:
+		result["type"] = "array"
+		result["items"] = jsonSchema(schema.Items)
+	case KindString:
+		result["type"] = "string"
+		if len(schema.Enum) > 0 {
+			result["enum"] = schema.Enum
+		}
+	case KindBool:
+		result["type"] = "boolean"
+	case KindInt:
+		result["type"] = "integer"
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package config
+
+// Migration upgrades a configuration file from schema version From to the
+// next version.
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(d *Document) error
+}
+
+// Migrations are all migrations ordered by From. A migration is added for
+// every increment of SchemaVersion.
+var Migrations = []Migration{
+	{
+		From:        0,
+		Description: "remove version, the version of the project is read from .gitver/.version",
+		Apply: func(d *Document) error {
+			d.remove("version")
+			return nil
+		},
+	},
+}
+
+// Migrate upgrades the document to SchemaVersion and returns the applied
+// migrations, none if the document is up to date.
+func (d *Document) Migrate() ([]Migration, error) {
+	version, err := d.SchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	if version > SchemaVersion {
+		return nil, NewerSchemaError(version)
+	}
+
+	var applied []Migration
+	for _, m := range Migrations {
+		if m.From != version {
+			continue
+		}
+
+		if err := m.Apply(d); err != nil {
+			return applied, err
+		}
+		version = m.From + 1
+		d.setSchemaVersion(version)
+		applied = append(applied, m)
+	}
+	return applied, nil
+}
@@ -0,0 +1,211 @@
+// Package config describes the configuration of a gitver project in
+// .gitver/config.yaml.
+//
+// Config is the typed configuration, settings are decoded into it strictly.
+// The schema derived from it lists every supported key with its type, its
+// allowed values and its default. It is used to validate configuration files
+// with the line and column of every problem, to export a JSON Schema and to
+// edit single keys of a file without touching the rest of it.
+package config
+
+import (
+	"fmt"
+	"gotver/internal/lifecycle"
+	"gotver/internal/notes"
+	"gotver/internal/policy"
+	"gotver/internal/publish"
+	"gotver/internal/version"
+	"reflect"
+	"strings"
+)
+
+// Sources a configuration value can come from, in the order of increasing
+// precedence.
+const (
+	SourceDefault = "default"
+	SourceUser    = "user"
+	SourceProject = "project"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// Kind is the type of a configuration value.
+type Kind int
+
+const (
+	KindObject Kind = iota
+	KindString
+	KindBool
+	KindInt
+	KindList
+	KindMap
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindObject:
+		return "object"
+	case KindString:
+		return "string"
+	case KindBool:
+		return "boolean"
+	case KindInt:
+		return "integer"
+	case KindList:
+		return "list"
+	case KindMap:
+		return "map"
+	default:
+		return "unknown"
+	}
+}
+
+// Schema describes a configuration value.
+type Schema struct {
+	Kind        Kind
+	Description string
+	// Default is the value used if the key is not configured, nil if there
+	// is none.
+	Default any
+	// Enum are the allowed values of a string.
+	Enum []string
+	// Fields are the keys of an object in the order they are listed.
+	Fields []Field
+	// Items is the schema of the items of a list and the values of a map.
+	Items *Schema
+	// Keys are the allowed keys of a map, every key is allowed if empty.
+	Keys []string
+}
+
+// Field is a key of an object.
+type Field struct {
+	Name string
+	*Schema
+}
+
+// names returns the keys of an object.
+func (s *Schema) names() []string {
+	names := make([]string, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// Field returns the field of an object. Keys are case-insensitive like in
+// viper.
+func (s *Schema) Field(name string) (Field, bool) {
+	for _, f := range s.Fields {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Root is the schema of the configuration file, derived from Config.
+var Root = schemaOf(reflect.TypeOf(Config{}), reflect.ValueOf(Default()), "", "")
+
+// enums are the allowed values of string keys and the allowed keys of maps
+// by key, the keys of list items are the keys of their list.
+var enums = map[string][]string{
+	"branches.type":    {policy.TypeRelease, policy.TypePreRelease, policy.TypeDeny},
+	"branches.maxBump": {version.SegmentMajor, version.SegmentMinor, version.SegmentPatch},
+	"propagation":      {"none", version.SegmentPatch, version.SegmentMinor, version.SegmentMajor},
+	"scheme.name":      {version.SchemeSemVer, version.SchemeCalVer, version.SchemeFourPart, version.SchemeMajorMinor, version.SchemeCustom},
+	"hooks":            lifecycle.Events,
+	"notes.templates":  {notes.FormatMarkdown, notes.FormatJSON, notes.FormatText},
+	"publish.provider": {publish.ProviderGitHub, publish.ProviderGitLab, publish.ProviderGitea},
+}
+
+// schemaOf derives the schema of a type. def is the default value, the
+// fields of structs are the keys of their mapstructure tags.
+func schemaOf(t reflect.Type, def reflect.Value, key, description string) *Schema {
+	schema := &Schema{Description: description}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		schema.Kind = KindObject
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := f.Tag.Get("mapstructure")
+			if name == "" || name == "-" {
+				continue
+			}
+			schema.Fields = append(schema.Fields, Field{name, schemaOf(f.Type, def.Field(i), join(key, name), f.Tag.Get("desc"))})
+		}
+		return schema
+	case reflect.String:
+		schema.Kind = KindString
+		schema.Enum = enums[key]
+	case reflect.Bool:
+		schema.Kind = KindBool
+	case reflect.Int:
+		schema.Kind = KindInt
+	case reflect.Slice:
+		schema.Kind = KindList
+		schema.Items = schemaOf(t.Elem(), reflect.Zero(t.Elem()), key, "")
+	case reflect.Map:
+		schema.Kind = KindMap
+		schema.Items = schemaOf(t.Elem(), reflect.Zero(t.Elem()), key+".*", "")
+		schema.Keys = enums[key]
+	default:
+		panic(fmt.Sprintf("config: unsupported type %s of key %s", t, key))
+	}
+
+	if t.Kind() == reflect.Bool || !def.IsZero() {
+		schema.Default = def.Interface()
+	}
+	return schema
+}
+
+// Lookup returns the schema of a dotted key like commits.firstParent and
+// the key in its canonical spelling.
+func Lookup(key string) (string, *Schema, error) {
+	schema := Root
+	names := strings.Split(key, ".")
+	for i, name := range names {
+		if schema.Kind == KindMap && i == len(names)-1 {
+			if len(schema.Keys) > 0 && !contains(schema.Keys, name) {
+				return "", nil, UnknownKeyError{Key: key, Suggestion: suggestKey(strings.Join(names[:i], "."), name, schema.Keys)}
+			}
+			schema = schema.Items
+			continue
+		}
+
+		f, ok := schema.Field(name)
+		if !ok {
+			return "", nil, UnknownKeyError{Key: key, Suggestion: suggestKey(strings.Join(names[:i], "."), name, schema.names())}
+		}
+		names[i], schema = f.Name, f.Schema
+	}
+	return strings.Join(names, "."), schema, nil
+}
+
+// Keys returns the keys of all values that are not objects in the order of
+// the schema, e.g. commits.firstParent but not commits.
+func Keys() []string {
+	var keys []string
+	var walk func(prefix string, schema *Schema)
+	walk = func(prefix string, schema *Schema) {
+		for _, f := range schema.Fields {
+			key := prefix + f.Name
+			if f.Kind == KindObject {
+				walk(key+".", f.Schema)
+				continue
+			}
+			keys = append(keys, key)
+		}
+	}
+	walk("", Root)
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// Problem is a violation of the schema at a 1-based line and column of the
+// configuration file.
+type Problem struct {
+	Line    int
+	Column  int
+	Key     string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", p.Line, p.Column, p.Key, p.Message)
+}
+
+// Validate checks a configuration file against the schema. An error is only
+// returned if the file is not valid YAML.
+func Validate(data []byte) ([]Problem, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, SyntaxError{error: err}
+	}
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+	return validateNode(document.Content[0], Root, ""), nil
+}
+
+// validateNode checks the node of the value of key against its schema.
+func validateNode(node *yaml.Node, schema *Schema, key string) []Problem {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	// An empty value is the same as an unset key.
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+
+	problem := func(node *yaml.Node, format string, args ...any) []Problem {
+		return []Problem{{Line: node.Line, Column: node.Column, Key: orRoot(key), Message: fmt.Sprintf(format, args...)}}
+	}
+	mismatch := func() []Problem {
+		return problem(node, "expected %s, got %s", schema.Kind, describe(node))
+	}
+
+	switch schema.Kind {
+	case KindObject:
+		if node.Kind != yaml.MappingNode {
+			return mismatch()
+		}
+		var problems []Problem
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name, value := node.Content[i], node.Content[i+1]
+			f, ok := schema.Field(name.Value)
+			if !ok {
+				problems = append(problems, Problem{Line: name.Line, Column: name.Column,
+					Key: join(key, name.Value), Message: unknownKey(name.Value, schema.names())})
+				continue
+			}
+			problems = append(problems, validateNode(value, f.Schema, join(key, f.Name))...)
+		}
+		return problems
+
+	case KindMap:
+		if node.Kind != yaml.MappingNode {
+			return mismatch()
+		}
+		var problems []Problem
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name, value := node.Content[i], node.Content[i+1]
+			if len(schema.Keys) > 0 && !contains(schema.Keys, name.Value) {
+				message := unknownKey(name.Value, schema.Keys)
+				if message == "unknown key" {
+					message = fmt.Sprintf("unknown key, use %s", strings.Join(schema.Keys, ", "))
+				}
+				problems = append(problems, Problem{Line: name.Line, Column: name.Column,
+					Key: join(key, name.Value), Message: message})
+				continue
+			}
+			problems = append(problems, validateNode(value, schema.Items, join(key, name.Value))...)
+		}
+		return problems
+
+	case KindList:
+		if node.Kind != yaml.SequenceNode {
+			return mismatch()
+		}
+		var problems []Problem
+		for i, item := range node.Content {
+			problems = append(problems, validateNode(item, schema.Items, fmt.Sprintf("%s[%d]", key, i))...)
+		}
+		return problems
+
+	case KindString:
+		// Scalars of other types are read as their text.
+		if node.Kind != yaml.ScalarNode {
+			return mismatch()
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, node.Value) {
+			return problem(node, "invalid value %q, use %s", node.Value, strings.Join(schema.Enum, ", "))
+		}
+		return nil
+
+	case KindBool:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!bool" {
+			return mismatch()
+		}
+		return nil
+
+	case KindInt:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			return mismatch()
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// describe names the type of a node for messages.
+func describe(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "list"
+	}
+
+	switch node.Tag {
+	case "!!bool":
+		return fmt.Sprintf("boolean %s", node.Value)
+	case "!!int":
+		return fmt.Sprintf("integer %s", node.Value)
+	case "!!float":
+		return fmt.Sprintf("number %s", node.Value)
+	default:
+		return fmt.Sprintf("string %q", node.Value)
+	}
+}
+
+// unknownKey is the message of an unknown key of an object.
+func unknownKey(name string, candidates []string) string {
+	if suggestion := suggestKey("", name, candidates); suggestion != "" {
+		return fmt.Sprintf("unknown key, did you mean %s?", suggestion)
+	}
+	return "unknown key"
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func orRoot(key string) string {
+	if key == "" {
+		return "(root)"
+	}
+	return key
+}
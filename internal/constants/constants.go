@@ -6,8 +6,12 @@ const (
 	ConfigType       = "yaml"
 	ConfigFolderName = ".gitver"
 	ProgrammName     = "gitver"
+	EnvPrefix        = "GITVER"
 	TagMessage       = "Tagged by gitver"
 	CommitMessage    = "Bump Version [%s] -> [%s]"
 	ReleaseTag       = "r%s"
 	VersionTag       = "v%s"
+
+	ComponentCommitMessage = "Bump Version %s"
+	ComponentChange        = "%s [%s] -> [%s]"
 )
@@ -0,0 +1,319 @@
+package exceptions
+
+import "sort"
+
+// Code is the stable number of a gitver error. Codes are grouped in ranges
+// of 1000 per package.
+type Code int
+
+// gitops
+const (
+	RepositoryNotFound Code = 1000
+	DetachedHead       Code = 1001
+	RevisionNotFound   Code = 1002
+	GitOperation       Code = 1003
+	DirtyRepository    Code = 1004
+	RemoteOperation    Code = 1005
+	TagNotFound        Code = 1006
+)
+
+// version
+const (
+	Unhandled                Code = 2000
+	FileAlreadyExists        Code = 2001
+	FileNotFound             Code = 2002
+	FileFormat               Code = 2003
+	ProjectDirectoryNotFound Code = 2004
+	InputValue               Code = 2005
+	WriteOperationFailed     Code = 2006
+	BumpNotAllowed           Code = 2007
+	VersionLine              Code = 2008
+	OutsideVersionLine       Code = 2009
+	UnknownSegment           Code = 2010
+	UnknownScheme            Code = 2011
+	CalVerFormat             Code = 2012
+	CalVerPeriod             Code = 2013
+	SegmentDefinition        Code = 2014
+	Constraint               Code = 2015
+)
+
+// xml
+const (
+	XMLRead            Code = 3000
+	XMLWrite           Code = 3001
+	XMLElementNotFound Code = 3002
+)
+
+// policy
+const (
+	NoMatchingPolicy Code = 4000
+	InvalidPattern   Code = 4001
+	InvalidPolicy    Code = 4002
+	BranchNotAllowed Code = 4003
+)
+
+// component
+const (
+	ComponentNotFound  Code = 5000
+	InvalidComponent   Code = 5001
+	TagTemplate        Code = 5002
+	CyclicDependency   Code = 5003
+	InvalidDependency  Code = 5004
+	ReferenceNotFound  Code = 5005
+	ManifestUpdate     Code = 5006
+	DuplicateComponent Code = 5007
+)
+
+// hooks
+const (
+	UnknownHook Code = 6000
+	HookExists  Code = 6001
+	HookWrite   Code = 6002
+)
+
+// lifecycle
+const (
+	UnknownEvent Code = 7000
+	HookFailed   Code = 7001
+)
+
+// notes
+const (
+	UnknownFormat Code = 8000
+	Render        Code = 8001
+)
+
+// publish
+const (
+	PublishConfig  Code = 9000
+	PublishRequest Code = 9001
+	PublishAPI     Code = 9002
+	PublishAsset   Code = 9003
+)
+
+// pkg/gitver
+const (
+	ProjectConfig     Code = 10000
+	ProjectRepository Code = 10001
+	NoChanges         Code = 10002
+	InvalidOptions    Code = 10003
+)
+
+// config
+const (
+	UnknownKey   Code = 11000
+	InvalidValue Code = 11001
+	ConfigSyntax Code = 11002
+	NewerSchema  Code = 11003
+)
+
+// Info describes an error code.
+type Info struct {
+	Code        Code
+	Name        string
+	Kind        error
+	Description string
+	Remediation string
+}
+
+var infos = map[Code]Info{
+	RepositoryNotFound: {RepositoryNotFound, "RepositoryNotFoundError", ErrGit,
+		"The project directory is not a git repository or it could not be opened.",
+		"Run gitver inside a git working tree or initialize one with `git init`."},
+	DetachedHead: {DetachedHead, "DetachedHeadError", ErrGit,
+		"HEAD does not point to a branch, so no branch policy can be applied.",
+		"Check out a branch, e.g. `git switch main`, or set the branch in CI."},
+	RevisionNotFound: {RevisionNotFound, "RevisionNotFoundError", ErrInvalidInput,
+		"A revision of a range could not be resolved to a commit.",
+		"Check the spelling of the tag, branch or commit and fetch missing tags with `git fetch --tags`."},
+	GitOperation: {GitOperation, "OperationError", ErrGit,
+		"A git operation like adding, committing, tagging or resetting failed.",
+		"Read the underlying error, fix the repository state and run the command again."},
+	DirtyRepository: {DirtyRepository, "DirtyRepositoryError", ErrDirtyRepository,
+		"The working tree has uncommitted changes, which would end up in the version commit.",
+		"Commit or stash the changes before bumping or releasing."},
+	RemoteOperation: {RemoteOperation, "RemoteError", ErrRemote,
+		"Pushing to the remote failed.",
+		"Check the remote URL, your credentials and whether the remote branch moved ahead (`git pull --rebase`)."},
+	TagNotFound: {TagNotFound, "TagNotFoundError", ErrGit,
+		"A tag of a version or of a tag range does not exist in the repository.",
+		"Check the version or the range and fetch missing tags with `git fetch --tags`."},
+
+	Unhandled: {Unhandled, "UnhandledError", ErrInternal,
+		"An unexpected error occurred.",
+		"Run the command with --log-level debug and report the output if the problem persists."},
+	FileAlreadyExists: {FileAlreadyExists, "FileAlreadyExistsError", ErrAlreadyExists,
+		"The version file already exists, so the project was initialized before.",
+		"Keep the existing .gitver/.version or remove it before running `gitver init` again."},
+	FileNotFound: {FileNotFound, "FileNotFoundError", ErrNotFound,
+		"The version file could not be read.",
+		"Run `gitver init` in the project directory or check the configured version file path."},
+	FileFormat: {FileFormat, "FileFormatError", ErrInvalidInput,
+		"The version file does not contain a version of the configured scheme.",
+		"Fix the content of the version file or configure the matching scheme."},
+	ProjectDirectoryNotFound: {ProjectDirectoryNotFound, "ProjectDirectoryNotFoundError", ErrNotFound,
+		"No .gitver folder was found in the current directory or any parent.",
+		"Run gitver inside a project or initialize one with `gitver init`."},
+	InputValue: {InputValue, "InputValueError", ErrInvalidInput,
+		"A version given as argument or flag could not be parsed.",
+		"Pass a version like 1.2.3 or 1.2.3-rc.1."},
+	WriteOperationFailed: {WriteOperationFailed, "WriteOperationFailedError", ErrIO,
+		"A version file could not be written.",
+		"Check the permissions of the .gitver folder and the free disk space."},
+	BumpNotAllowed: {BumpNotAllowed, "BumpNotAllowedError", ErrNotAllowed,
+		"The branch policy limits bumps to less significant segments.",
+		"Bump a less significant segment or bump on a branch whose policy allows it."},
+	VersionLine: {VersionLine, "VersionLineError", ErrInvalidConfig,
+		"A maintenance branch name is not a version line like 1.4 or 1.4.x.",
+		"Name maintenance branches after their version line, e.g. release/1.4.x."},
+	OutsideVersionLine: {OutsideVersionLine, "OutsideVersionLineError", ErrNotAllowed,
+		"The bump would leave the version line of the maintenance branch.",
+		"Bump a less significant segment or release the change from the main branch."},
+	UnknownSegment: {UnknownSegment, "UnknownSegmentError", ErrInvalidInput,
+		"The segment is not part of the versioning scheme.",
+		"Use one of the segments of the configured scheme."},
+	UnknownScheme: {UnknownScheme, "UnknownSchemeError", ErrInvalidConfig,
+		"The configured versioning scheme is not known.",
+		"Set scheme.name to semver, fourpart, majorminor, calver or custom."},
+	CalVerFormat: {CalVerFormat, "CalVerFormatError", ErrInvalidConfig,
+		"The calendar version format contains unknown tokens or MICRO is not last.",
+		"Use tokens like YYYY, 0M, DD and MICRO separated by dots, e.g. YYYY.0M.MICRO."},
+	CalVerPeriod: {CalVerPeriod, "CalVerPeriodError", ErrNotAllowed,
+		"The calendar version is already current and the format has no MICRO counter.",
+		"Add MICRO to the format or wait for the next period."},
+	SegmentDefinition: {SegmentDefinition, "SegmentDefinitionError", ErrInvalidConfig,
+		"A custom scheme needs at least one segment and unique segment names.",
+		"Fix scheme.segments in the configuration."},
+	Constraint: {Constraint, "ConstraintError", ErrInvalidInput,
+		"The version constraint could not be parsed.",
+		"Use constraints like `>=1.2 <2`, `^1.4`, `~1.2` or `1.2 - 1.5`."},
+
+	XMLRead: {XMLRead, "ReadError", ErrIO,
+		"An XML manifest could not be read or parsed.",
+		"Check that the file exists and is well-formed XML."},
+	XMLWrite: {XMLWrite, "WriteError", ErrIO,
+		"An XML manifest could not be written.",
+		"Check the permissions of the file."},
+	XMLElementNotFound: {XMLElementNotFound, "ElementNotFoundError", ErrNotFound,
+		"The configured XPath does not match any element of the XML manifest.",
+		"Fix the xpath of the dependency or updater, e.g. ./project/version."},
+
+	NoMatchingPolicy: {NoMatchingPolicy, "NoMatchingPolicyError", ErrNotAllowed,
+		"Branch policies are configured, but none matches the current branch.",
+		"Add a policy for the branch or a catch-all pattern like `*`."},
+	InvalidPattern: {InvalidPattern, "InvalidPatternError", ErrInvalidConfig,
+		"A branch policy pattern is not a valid glob.",
+		"Fix the pattern in the branches configuration."},
+	InvalidPolicy: {InvalidPolicy, "InvalidPolicyError", ErrInvalidConfig,
+		"A branch policy has an unknown type, a pre-release policy has no identifier or the bump limit is unknown.",
+		"Use the types release, prerelease or deny and the limits major, minor or patch."},
+	BranchNotAllowed: {BranchNotAllowed, "BranchNotAllowedError", ErrNotAllowed,
+		"The policy of the current branch denies versioning.",
+		"Bump on a branch whose policy allows it."},
+
+	ComponentNotFound: {ComponentNotFound, "ComponentNotFoundError", ErrInvalidInput,
+		"The component is not configured.",
+		"Use one of the names configured under components."},
+	InvalidComponent: {InvalidComponent, "InvalidComponentError", ErrInvalidConfig,
+		"A component has no name or no path.",
+		"Configure name and path for every component."},
+	TagTemplate: {TagTemplate, "TagTemplateError", ErrInvalidConfig,
+		"The tag template of a component could not be parsed or executed.",
+		"Use a Go template with .Name and .Version, e.g. {{.Name}}/v{{.Version}}."},
+	CyclicDependency: {CyclicDependency, "CyclicDependencyError", ErrInvalidConfig,
+		"The component dependencies form a cycle.",
+		"Remove one of the dependencies of the printed cycle."},
+	InvalidDependency: {InvalidDependency, "InvalidDependencyError", ErrInvalidConfig,
+		"A dependency has no name or its file has neither an xpath nor a pattern with a capture group.",
+		"Configure name, and for a file an xpath or a pattern with one capture group."},
+	ReferenceNotFound: {ReferenceNotFound, "ReferenceNotFoundError", ErrNotFound,
+		"The dependency pattern does not match the manifest.",
+		"Fix the pattern so its capture group matches the referenced version."},
+	ManifestUpdate: {ManifestUpdate, "ManifestUpdateError", ErrIO,
+		"A dependency reference in a manifest could not be updated.",
+		"Check that the manifest exists and is writable."},
+	DuplicateComponent: {DuplicateComponent, "DuplicateComponentError", ErrInvalidConfig,
+		"A component name is configured more than once.",
+		"Give every component a unique name."},
+
+	UnknownHook: {UnknownHook, "UnknownHookError", ErrInvalidInput,
+		"The git hook cannot be installed by gitver.",
+		"Install the commit-msg or pre-push hook."},
+	HookExists: {HookExists, "HookExistsError", ErrAlreadyExists,
+		"A foreign hook and its preserved .local copy both exist.",
+		"Merge or remove the .local hook before installing again."},
+	HookWrite: {HookWrite, "HookWriteError", ErrIO,
+		"A git hook could not be written.",
+		"Check the permissions of the hooks directory."},
+
+	UnknownEvent: {UnknownEvent, "UnknownEventError", ErrInvalidConfig,
+		"A lifecycle hook is configured for an unknown event.",
+		"Use pre-bump, post-version-write, pre-commit, post-tag or post-push."},
+	HookFailed: {HookFailed, "HookFailedError", ErrHook,
+		"A lifecycle hook exited with an error, the bump was rolled back.",
+		"Read the printed hook output and fix the hook command."},
+
+	UnknownFormat: {UnknownFormat, "UnknownFormatError", ErrInvalidInput,
+		"The release notes format is not known.",
+		"Use markdown, json or text."},
+	Render: {Render, "RenderError", ErrInvalidInput,
+		"The release notes template could not be parsed or executed.",
+		"Fix the custom template given with --template or notes.templates."},
+
+	PublishConfig: {PublishConfig, "InvalidConfigError", ErrInvalidConfig,
+		"The publish configuration is incomplete.",
+		"Set publish.provider, publish.repository and, for gitea and gitlab, publish.baseUrl."},
+	PublishRequest: {PublishRequest, "RequestError", ErrRemote,
+		"The forge API could not be reached.",
+		"Check publish.baseUrl and your network connection."},
+	PublishAPI: {PublishAPI, "APIError", ErrRemote,
+		"The forge API rejected a request.",
+		"Check the API token in publish.tokenEnv and its permissions, and that the tag was pushed."},
+	PublishAsset: {PublishAsset, "AssetError", ErrRemote,
+		"A release asset could not be read or uploaded.",
+		"Build the assets before publishing and check the paths in publish.assets."},
+
+	ProjectConfig: {ProjectConfig, "ConfigError", ErrInvalidConfig,
+		"The configuration of the project could not be read.",
+		"Check that .gitver/config.yaml exists and is valid YAML."},
+	ProjectRepository: {ProjectRepository, "RepositoryError", ErrGit,
+		"The git repository of the project could not be opened.",
+		"Open a project inside a git working tree."},
+	NoChanges: {NoChanges, "NoChangesError", ErrNoChange,
+		"The commits since the last release do not require a new version.",
+		"Nothing to do. Use conventional commit types like feat or fix, or bump a segment explicitly."},
+	InvalidOptions: {InvalidOptions, "InvalidOptionsError", ErrInvalidInput,
+		"The options of a library call are invalid or contradict each other.",
+		"Check the options passed, e.g. give either a version or a tag range."},
+
+	UnknownKey: {UnknownKey, "UnknownKeyError", ErrInvalidConfig,
+		"The configuration key is not supported by gitver.",
+		"Fix the spelling of the key, `gitver config list` shows all supported keys."},
+	InvalidValue: {InvalidValue, "InvalidValueError", ErrInvalidConfig,
+		"A configuration value has the wrong type or is not one of the allowed values.",
+		"Fix the value at the reported location, `gitver config validate` checks the whole file."},
+	ConfigSyntax: {ConfigSyntax, "SyntaxError", ErrInvalidConfig,
+		"The configuration file is not valid YAML.",
+		"Fix the YAML syntax at the reported line of .gitver/config.yaml."},
+	NewerSchema: {NewerSchema, "NewerSchemaError", ErrInvalidConfig,
+		"The configuration has a schema version of a newer gitver.",
+		"Update gitver to the version the configuration was written with."},
+}
+
+// Lookup returns the description of a code.
+func Lookup(code Code) (Info, bool) {
+	info, ok := infos[code]
+	return info, ok
+}
+
+// All returns the descriptions of all codes ordered by code.
+func All() []Info {
+	all := make([]Info, 0, len(infos))
+	for _, info := range infos {
+		all = append(all, info)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Code < all[j].Code
+	})
+	return all
+}
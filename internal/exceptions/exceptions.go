@@ -0,0 +1,122 @@
+// Package exceptions is the error model shared by all gitver packages.
+//
+// Every error type of a package reports a stable numeric Code and unwraps to
+// the sentinel Kind it belongs to and, if any, to its underlying cause, so
+// callers can use errors.Is with a kind or a cause and errors.As with the
+// concrete type. Codes are grouped in ranges per package and never reused.
+package exceptions
+
+import "errors"
+
+// Kinds of errors. Every error type unwraps to exactly one kind.
+var (
+	ErrInternal        = errors.New("internal error")
+	ErrInvalidInput    = errors.New("invalid input")
+	ErrInvalidConfig   = errors.New("invalid configuration")
+	ErrNotFound        = errors.New("not found")
+	ErrAlreadyExists   = errors.New("already exists")
+	ErrNotAllowed      = errors.New("not allowed")
+	ErrIO              = errors.New("file operation failed")
+	ErrGit             = errors.New("git operation failed")
+	ErrDirtyRepository = errors.New("git repository is not clean")
+	ErrRemote          = errors.New("remote operation failed")
+	ErrHook            = errors.New("hook failed")
+	ErrNoChange        = errors.New("no new version required")
+)
+
+// Process exit codes.
+const (
+	ExitOK         = 0
+	ExitFailure    = 1
+	ExitValidation = 2
+	ExitConfig     = 3
+	ExitGit        = 4
+	ExitDirty      = 5
+	ExitRemote     = 6
+	ExitNoChange   = 10
+)
+
+// Coder is implemented by all gitver error types.
+type Coder interface {
+	error
+	Code() Code
+}
+
+// CodeOf returns the code of the first gitver error in the chain of err, or
+// 0 if there is none.
+func CodeOf(err error) Code {
+	var coder Coder
+	if errors.As(err, &coder) {
+		return coder.Code()
+	}
+	return 0
+}
+
+// KindOf returns the kind of the first gitver error in the chain of err. For
+// other errors the first kind found in the chain is returned, ErrInternal if
+// there is none.
+func KindOf(err error) error {
+	if info, ok := Lookup(CodeOf(err)); ok {
+		return info.Kind
+	}
+
+	for _, kind := range kinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return ErrInternal
+}
+
+var kinds = []error{
+	ErrNoChange,
+	ErrDirtyRepository,
+	ErrInvalidConfig,
+	ErrInvalidInput,
+	ErrNotAllowed,
+	ErrAlreadyExists,
+	ErrNotFound,
+	ErrRemote,
+	ErrGit,
+	ErrHook,
+	ErrIO,
+	ErrInternal,
+}
+
+// ExitCode returns the process exit code for err.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	switch KindOf(err) {
+	case ErrNoChange:
+		return ExitNoChange
+	case ErrDirtyRepository:
+		return ExitDirty
+	case ErrInvalidConfig:
+		return ExitConfig
+	case ErrInvalidInput, ErrNotAllowed, ErrAlreadyExists, ErrNotFound:
+		return ExitValidation
+	case ErrGit:
+		return ExitGit
+	case ErrRemote:
+		return ExitRemote
+	default:
+		return ExitFailure
+	}
+}
+
+// Unwrap returns the errors a gitver error unwraps to: the kind of its code
+// and, if not nil, its cause.
+func Unwrap(code Code, cause error) []error {
+	kind := ErrInternal
+	if info, ok := Lookup(code); ok {
+		kind = info.Kind
+	}
+
+	if cause == nil {
+		return []error{kind}
+	}
+	return []error{kind, cause}
+}
@@ -1,5 +1,114 @@
 package gitops
 
-const (
-	errorCode1000 = iota + 1000
+import (
+	"fmt"
+	"gotver/internal/exceptions"
 )
+
+type RepositoryNotFoundError struct {
+	path  string
+	error error
+}
+
+func (p RepositoryNotFoundError) Error() string {
+	return fmt.Sprintf("error code: %d - git repository %q could not be opened: %v", exceptions.RepositoryNotFound, p.path, p.error)
+}
+
+func (p RepositoryNotFoundError) Code() exceptions.Code {
+	return exceptions.RepositoryNotFound
+}
+
+func (p RepositoryNotFoundError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.RepositoryNotFound, p.error)
+}
+
+type DetachedHeadError string
+
+func (p DetachedHeadError) Error() string {
+	return fmt.Sprintf("error code: %d - HEAD is detached at %q", exceptions.DetachedHead, string(p))
+}
+
+func (p DetachedHeadError) Code() exceptions.Code {
+	return exceptions.DetachedHead
+}
+
+func (p DetachedHeadError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.DetachedHead, nil)
+}
+
+type RevisionNotFoundError string
+
+func (p RevisionNotFoundError) Error() string {
+	return fmt.Sprintf("error code: %d - revision %q not found", exceptions.RevisionNotFound, string(p))
+}
+
+func (p RevisionNotFoundError) Code() exceptions.Code {
+	return exceptions.RevisionNotFound
+}
+
+func (p RevisionNotFoundError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.RevisionNotFound, nil)
+}
+
+type TagNotFoundError string
+
+func (p TagNotFoundError) Error() string {
+	return fmt.Sprintf("error code: %d - tag %q not found", exceptions.TagNotFound, string(p))
+}
+
+func (p TagNotFoundError) Code() exceptions.Code {
+	return exceptions.TagNotFound
+}
+
+func (p TagNotFoundError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.TagNotFound, nil)
+}
+
+type OperationError struct {
+	operation string
+	error     error
+}
+
+func (p OperationError) Error() string {
+	return fmt.Sprintf("error code: %d - git %s failed: %v", exceptions.GitOperation, p.operation, p.error)
+}
+
+func (p OperationError) Code() exceptions.Code {
+	return exceptions.GitOperation
+}
+
+func (p OperationError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.GitOperation, p.error)
+}
+
+type DirtyRepositoryError string
+
+func (p DirtyRepositoryError) Error() string {
+	return fmt.Sprintf("error code: %d - git repository %q is not clean", exceptions.DirtyRepository, string(p))
+}
+
+func (p DirtyRepositoryError) Code() exceptions.Code {
+	return exceptions.DirtyRepository
+}
+
+func (p DirtyRepositoryError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.DirtyRepository, nil)
+}
+
+type RemoteError struct {
+	remote    string
+	operation string
+	error     error
+}
+
+func (p RemoteError) Error() string {
+	return fmt.Sprintf("error code: %d - %s to %q failed: %v", exceptions.RemoteOperation, p.operation, p.remote, p.error)
+}
+
+func (p RemoteError) Code() exceptions.Code {
+	return exceptions.RemoteOperation
+}
+
+func (p RemoteError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.RemoteOperation, p.error)
+}
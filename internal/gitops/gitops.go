@@ -1,13 +1,17 @@
 package gitops
 
 import (
-	"fmt"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	"gotver/internal/constants"
+	"io"
+	"log/slog"
+	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -26,6 +30,12 @@ type GitOps struct {
 	head          *plumbing.Reference
 	commitMessage string
 	tagMessage    string
+	firstParent   bool
+	mergesOnly    bool
+	// injected is set when the repository was set with SetRepository
+	// instead of being opened from path.
+	injected bool
+	logger   *slog.Logger
 }
 
 func init() {
@@ -36,9 +46,19 @@ func New() *GitOps {
 	g := new(GitOps)
 	g.commitMessage = constants.CommitMessage
 	g.tagMessage = constants.TagMessage
+	g.logger = slog.Default()
 	return g
 }
 
+func SetLogger(logger *slog.Logger) {
+	g.SetLogger(logger)
+}
+
+// SetLogger sets the logger the git operations are logged to.
+func (g *GitOps) SetLogger(logger *slog.Logger) {
+	g.logger = logger
+}
+
 func SetRepositoryPath(path string) {
 	g.SetRepositoryPath(path)
 }
@@ -47,21 +67,50 @@ func (g *GitOps) SetRepositoryPath(path string) {
 	g.path = path
 }
 
+func SetRepository(r *git.Repository) error {
+	return g.SetRepository(r)
+}
+
+// SetRepository uses an already opened repository, e.g. one held in memory,
+// instead of opening the repository at the repository path. ReadRepository
+// keeps the repository afterwards.
+func (g *GitOps) SetRepository(r *git.Repository) error {
+	w, err := r.Worktree()
+	if err != nil {
+		return RepositoryNotFoundError{g.path, err}
+	}
+
+	g.repository = r
+	g.worktree = w
+	g.injected = true
+	return nil
+}
+
 func ReadRepository() error {
 	return g.ReadRepository()
 }
 
 func (g *GitOps) ReadRepository() error {
+	if g.injected {
+		return nil
+	}
 
 	r, err := git.PlainOpen(g.path)
 	if err != nil {
-		// RepositoryNotExists
-		return err
+		return RepositoryNotFoundError{g.path, err}
 	}
 
+	w, err := r.Worktree()
+	if err != nil {
+		return RepositoryNotFoundError{g.path, err}
+	}
+
+	g.repository = r
+	g.worktree = w
+
 	cfg, err := r.ConfigScoped(config.GlobalScope)
 	if err != nil {
-		return err
+		return OperationError{"config", err}
 	}
 
 	user := cfg.User
@@ -69,15 +118,8 @@ func (g *GitOps) ReadRepository() error {
 		return nil
 	}
 
-	w, err := r.Worktree()
-	if err != nil {
-		return err
-	}
-
 	g.name = user.Name
 	g.email = user.Email
-	g.repository = r
-	g.worktree = w
 
 	return nil
 }
@@ -87,7 +129,11 @@ func GetStatus() (git.Status, error) {
 }
 
 func (g *GitOps) GetStatus() (git.Status, error) {
-	return g.worktree.Status()
+	status, err := g.worktree.Status()
+	if err != nil {
+		return nil, OperationError{"status", err}
+	}
+	return status, nil
 }
 
 func IsCleanRepo() (bool, error) {
@@ -107,7 +153,11 @@ func Add() (plumbing.Hash, error) {
 }
 
 func (g *GitOps) Add() (plumbing.Hash, error) {
-	return g.worktree.Add(".")
+	hash, err := g.worktree.Add(".")
+	if err != nil {
+		return hash, OperationError{"add", err}
+	}
+	return hash, nil
 }
 
 func Commit(message string, amend bool) error {
@@ -132,10 +182,11 @@ func (g *GitOps) Commit(message string, amend bool) error {
 		commitOptions.Parents = []plumbing.Hash{headRef.Hash()}
 	}
 
-	_, err := g.worktree.Commit(message, commitOptions)
+	hash, err := g.worktree.Commit(message, commitOptions)
 	if err != nil {
-		return err
+		return OperationError{"commit", err}
 	}
+	g.logger.Debug("commit created", "commit", hash.String(), "amend", amend)
 	return nil
 }
 
@@ -165,18 +216,79 @@ func (g *GitOps) CreateTag(tag string, message string) error {
 		Message: message,
 	})
 	if err != nil {
-		return err
+		return OperationError{"tag", err}
 	}
+	g.logger.Debug("tag created", "tag", tag, "commit", headCommit.Hash.String())
 
 	return nil
 }
 
+func GetTags() ([]string, error) {
+	return g.GetTags()
+}
+
+// GetTags returns the names of all tags of the repository.
+func (g *GitOps) GetTags() ([]string, error) {
+	tagRefs, err := g.repository.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	err = tagRefs.ForEach(func(t *plumbing.Reference) error {
+		tags = append(tags, t.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func DeleteTag(tag string) error {
+	return g.DeleteTag(tag)
+}
+
+func (g *GitOps) DeleteTag(tag string) error {
+	if err := g.repository.DeleteTag(tag); err != nil {
+		return OperationError{"tag --delete", err}
+	}
+	return nil
+}
+
+func ResetHard(hash plumbing.Hash) error {
+	return g.ResetHard(hash)
+}
+
+// ResetHard resets HEAD, index and worktree to the commit, discarding all
+// changes made since.
+func (g *GitOps) ResetHard(hash plumbing.Hash) error {
+	if err := g.worktree.Reset(&git.ResetOptions{Commit: hash, Mode: git.HardReset}); err != nil {
+		return OperationError{"reset --hard", err}
+	}
+	return nil
+}
+
 func GetLastTag() (string, error) {
 	return g.GetLatestTag()
 }
 
+// GetLatestTag returns the newest tag reachable from HEAD, so tags created
+// on other branches (e.g. newer releases on main while working on a
+// maintenance branch) are not taken into account.
 func (g *GitOps) GetLatestTag() (string, error) {
 
+	headRef, err := g.repository.Head()
+	if err != nil {
+		return "", err
+	}
+
+	reachable, err := g.reachableCommits(headRef.Hash())
+	if err != nil {
+		return "", err
+	}
+
 	tagRefs, err := g.repository.Tags()
 	if err != nil {
 		return "", err
@@ -195,12 +307,18 @@ func (g *GitOps) GetLatestTag() (string, error) {
 			if err != nil {
 				return nil // Ignorieren von Fehlern, die durch leichte Tags verursacht werden
 			}
+			if !reachable[commit.Hash] {
+				return nil
+			}
 			tags = append(tags, struct {
 				Name string
 				When time.Time
 			}{t.Name().Short(), commit.Committer.When})
 			return nil
 		}
+		if !reachable[obj.Target] {
+			return nil
+		}
 		tags = append(tags, struct {
 			Name string
 			When time.Time
@@ -216,13 +334,66 @@ func (g *GitOps) GetLatestTag() (string, error) {
 		return tags[i].When.After(tags[j].When)
 	})
 
+	g.logger.Debug("tags reachable from HEAD found", "count", len(tags))
 	if len(tags) > 0 {
+		g.logger.Debug("latest tag found", "tag", tags[0].Name)
 		return tags[0].Name, nil
 	}
 
 	return "", nil
 }
 
+func GetReachableTags() ([]string, error) {
+	return g.GetReachableTags()
+}
+
+// GetReachableTags returns the names of the tags pointing to commits
+// reachable from HEAD.
+func (g *GitOps) GetReachableTags() ([]string, error) {
+	headRef, err := g.repository.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	reachable, err := g.reachableCommits(headRef.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := g.GetTags()
+	if err != nil {
+		return nil, err
+	}
+
+	reachableTags := []string{}
+	for _, tag := range tags {
+		if hash, err := g.GetTag(tag); err == nil && reachable[hash] {
+			reachableTags = append(reachableTags, tag)
+		}
+	}
+	return reachableTags, nil
+}
+
+// reachableCommits returns the hashes of all commits reachable from the
+// given commit, including the commit itself.
+func (g *GitOps) reachableCommits(from plumbing.Hash) (map[plumbing.Hash]bool, error) {
+	commitIter, err := g.repository.Log(&git.LogOptions{From: from})
+	if err != nil {
+		return nil, err
+	}
+
+	reachable := make(map[plumbing.Hash]bool)
+	err = commitIter.ForEach(func(c *object.Commit) error {
+		reachable[c.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reachable, nil
+}
+
 func Push() error {
 	return g.Push()
 }
@@ -246,12 +417,155 @@ func (g *GitOps) Push() error {
 		RefSpecs:   []config.RefSpec{config.RefSpec(ref.Name() + ":" + ref.Name())},
 	})
 	if err != nil {
-		return err
+		return RemoteError{"origin", "push", err}
 	}
+	g.logger.Debug("branch pushed", "branch", ref.Name().Short(), "remote", "origin")
 
 	return nil
 }
 
+func PushTag(tag string) error {
+	return g.PushTag(tag)
+}
+
+// PushTag pushes a single tag to origin. A tag that is already up to date
+// on the remote is not an error.
+func (g *GitOps) PushTag(tag string) error {
+	ref := plumbing.NewTagReferenceName(tag)
+	err := g.repository.Push(&git.PushOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{config.RefSpec(ref + ":" + ref)},
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return RemoteError{"origin", "push tag", err}
+	}
+	g.logger.Debug("tag pushed", "tag", tag, "remote", "origin")
+	return nil
+}
+
+func GetHeadTags() ([]string, error) {
+	return g.GetHeadTags()
+}
+
+// GetHeadTags returns the names of the tags pointing to the HEAD commit.
+func (g *GitOps) GetHeadTags() ([]string, error) {
+	headRef, err := g.repository.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := g.GetTags()
+	if err != nil {
+		return nil, err
+	}
+
+	headTags := []string{}
+	for _, tag := range tags {
+		hash, err := g.GetTag(tag)
+		if err == nil && hash == headRef.Hash() {
+			headTags = append(headTags, tag)
+		}
+	}
+	return headTags, nil
+}
+
+// UpstreamStatus describes how the HEAD branch relates to the branch it
+// tracks.
+type UpstreamStatus struct {
+	Branch string `json:"branch"`
+	// Upstream is the short name of the tracked branch, e.g. origin/main. It
+	// is empty if the branch does not track a branch.
+	Upstream string `json:"upstream,omitempty"`
+	// Gone reports that the tracked branch does not exist (anymore).
+	Gone   bool `json:"gone,omitempty"`
+	Ahead  int  `json:"ahead"`
+	Behind int  `json:"behind"`
+}
+
+func GetUpstreamStatus() (UpstreamStatus, error) {
+	return g.GetUpstreamStatus()
+}
+
+// GetUpstreamStatus returns the number of commits the HEAD branch is ahead
+// and behind the branch it tracks, like git status does. The remote is not
+// fetched, the remote-tracking branch is compared as it is.
+func (g *GitOps) GetUpstreamStatus() (UpstreamStatus, error) {
+	branch, err := g.GetBranchName()
+	if err != nil {
+		return UpstreamStatus{}, err
+	}
+	status := UpstreamStatus{Branch: branch}
+
+	cfg, err := g.repository.Config()
+	if err != nil {
+		return status, OperationError{"config", err}
+	}
+
+	tracking, ok := cfg.Branches[branch]
+	if !ok || tracking.Remote == "" || tracking.Merge == "" {
+		return status, nil
+	}
+
+	upstreamName := tracking.Merge
+	if tracking.Remote != "." {
+		upstreamName = plumbing.NewRemoteReferenceName(tracking.Remote, tracking.Merge.Short())
+	}
+	status.Upstream = upstreamName.Short()
+
+	upstreamRef, err := g.repository.Reference(upstreamName, true)
+	if err != nil {
+		status.Gone = true
+		return status, nil
+	}
+
+	headRef, err := g.repository.Head()
+	if err != nil {
+		return status, err
+	}
+
+	local, err := g.reachableCommits(headRef.Hash())
+	if err != nil {
+		return status, err
+	}
+	upstream, err := g.reachableCommits(upstreamRef.Hash())
+	if err != nil {
+		return status, err
+	}
+
+	for hash := range local {
+		if !upstream[hash] {
+			status.Ahead++
+		}
+	}
+	for hash := range upstream {
+		if !local[hash] {
+			status.Behind++
+		}
+	}
+	g.logger.Debug("upstream compared", "branch", branch, "upstream", status.Upstream,
+		"ahead", status.Ahead, "behind", status.Behind)
+
+	return status, nil
+}
+
+func GetBranchName() (string, error) {
+	return g.GetBranchName()
+}
+
+// GetBranchName returns the short name of the branch HEAD points to.
+func (g *GitOps) GetBranchName() (string, error) {
+	headRef, err := g.repository.Head()
+	if err != nil {
+		return "", err
+	}
+
+	if !headRef.Name().IsBranch() {
+		return "", DetachedHeadError(headRef.Hash().String())
+	}
+
+	return headRef.Name().Short(), nil
+}
+
 func GetHeadCommit() (*object.Commit, error) {
 	return g.GetHeadCommit()
 }
@@ -294,6 +608,10 @@ func (g *GitOps) GetTag(tag string) (plumbing.Hash, error) {
 }
 
 func HasTag(tag string) bool {
+	return g.HasTag(tag)
+}
+
+func (g *GitOps) HasTag(tag string) bool {
 	hash, _ := g.GetTag(tag)
 	return !hash.IsZero()
 }
@@ -302,10 +620,13 @@ func GetCommitsBetweenTags(tagStart, tagEnd string) ([]*object.Commit, error) {
 	return g.GetCommitsBetweenTags(tagStart, tagEnd)
 }
 
+// GetCommitsBetweenTags returns the commits reachable from startTag but not
+// from endTag, like `git log endTag..startTag`. An empty or unknown startTag
+// starts at HEAD, an empty or unknown endTag walks the whole history.
 func (g *GitOps) GetCommitsBetweenTags(startTag, endTag string) ([]*object.Commit, error) {
 
 	startHash, err := g.GetTag(startTag)
-	if err != nil {
+	if err != nil || startHash.IsZero() {
 		headRef, err := g.repository.Head()
 		if err != nil {
 			return nil, err
@@ -313,29 +634,219 @@ func (g *GitOps) GetCommitsBetweenTags(startTag, endTag string) ([]*object.Commi
 		startHash = headRef.Hash()
 	}
 
-	endHash, _ := g.GetTag(endTag)
+	endHash, err := g.GetTag(endTag)
 	if err != nil {
-		endHash = plumbing.Hash{}
+		endHash = plumbing.ZeroHash
+	}
+
+	return g.commitsBetween(startHash, endHash)
+}
+
+func GetCommitsInRange(revRange string) ([]*object.Commit, error) {
+	return g.GetCommitsInRange(revRange)
+}
+
+// GetCommitsInRange returns the commits of a revision range like `A..B` or
+// a single revision, which includes its whole history.
+func (g *GitOps) GetCommitsInRange(revRange string) ([]*object.Commit, error) {
+	from, to, found := strings.Cut(revRange, "..")
+	if !found {
+		from, to = "", revRange
+	}
+	if to == "" {
+		to = "HEAD"
 	}
 
-	logOptions := &git.LogOptions{From: startHash}
-	commitIter, err := g.repository.Log(logOptions)
+	startHash, err := g.repository.ResolveRevision(plumbing.Revision(to))
+	if err != nil {
+		return nil, RevisionNotFoundError(to)
+	}
+
+	endHash := plumbing.ZeroHash
+	if from != "" {
+		hash, err := g.repository.ResolveRevision(plumbing.Revision(from))
+		if err != nil {
+			return nil, RevisionNotFoundError(from)
+		}
+		endHash = *hash
+	}
+
+	return g.commitsBetween(*startHash, endHash)
+}
+
+// commitsBetween returns the commits reachable from startHash but not from
+// endHash. A zero endHash walks the whole history.
+func (g *GitOps) commitsBetween(startHash, endHash plumbing.Hash) ([]*object.Commit, error) {
+	excluded := map[plumbing.Hash]bool{}
+	if !endHash.IsZero() {
+		var err error
+		excluded, err = g.reachableCommits(endHash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	startCommit, err := g.repository.CommitObject(startHash)
 	if err != nil {
 		return nil, err
 	}
 
+	var commitIter object.CommitIter
+	if g.firstParent {
+		commitIter = newFirstParentIter(startCommit, excluded)
+	} else {
+		commitIter = object.NewCommitPreorderIter(startCommit, excluded, nil)
+	}
+
 	var commits []*object.Commit
 	err = commitIter.ForEach(func(c *object.Commit) error {
-		if c.Hash == endHash {
-			return fmt.Errorf("BREAK")
+		if g.mergesOnly && c.NumParents() < 2 {
+			return nil
 		}
 		commits = append(commits, c)
 		return nil
 	})
-
-	if err != nil && err.Error() != "BREAK" {
+	if err != nil {
 		return nil, err
 	}
+	g.logger.Debug("commits matched", "from", startHash.String(), "to", endHash.String(),
+		"count", len(commits), "firstParent", g.firstParent, "mergesOnly", g.mergesOnly)
 
 	return commits, nil
 }
+
+func GetChangedFiles(c *object.Commit) ([]string, error) {
+	return g.GetChangedFiles(c)
+}
+
+// GetChangedFiles returns the paths touched by a commit compared to its first
+// parent. For root commits all files of the commit are returned.
+func (g *GitOps) GetChangedFiles(c *object.Commit) ([]string, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	var parentTree *object.Tree
+	if c.NumParents() > 0 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		parentTree, err = parent.Tree()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, change := range changes {
+		if change.From.Name != "" {
+			files = append(files, change.From.Name)
+		}
+		if change.To.Name != "" && change.To.Name != change.From.Name {
+			files = append(files, change.To.Name)
+		}
+	}
+
+	return files, nil
+}
+
+func GetHooksDirectory() (string, error) {
+	return g.GetHooksDirectory()
+}
+
+// GetHooksDirectory returns the directory git runs hooks from, honouring
+// core.hooksPath.
+func (g *GitOps) GetHooksDirectory() (string, error) {
+	cfg, err := g.repository.Config()
+	if err != nil {
+		return "", err
+	}
+
+	if hooksPath := cfg.Raw.Section("core").Option("hooksPath"); hooksPath != "" {
+		if filepath.IsAbs(hooksPath) {
+			return hooksPath, nil
+		}
+		return filepath.Join(g.path, hooksPath), nil
+	}
+
+	return filepath.Join(g.path, git.GitDirName, "hooks"), nil
+}
+
+func SetFirstParent(firstParent bool) {
+	g.SetFirstParent(firstParent)
+}
+
+// SetFirstParent restricts commit analysis to the first parent of merge
+// commits, like `git log --first-parent`.
+func (g *GitOps) SetFirstParent(firstParent bool) {
+	g.firstParent = firstParent
+}
+
+func SetMergesOnly(mergesOnly bool) {
+	g.SetMergesOnly(mergesOnly)
+}
+
+// SetMergesOnly restricts commit analysis to merge commits, so only the
+// merge or pull request titles are analysed.
+func (g *GitOps) SetMergesOnly(mergesOnly bool) {
+	g.mergesOnly = mergesOnly
+}
+
+// firstParentIter walks the first-parent chain of a commit until it reaches
+// an excluded commit or the root.
+type firstParentIter struct {
+	next     *object.Commit
+	excluded map[plumbing.Hash]bool
+}
+
+func newFirstParentIter(c *object.Commit, excluded map[plumbing.Hash]bool) object.CommitIter {
+	return &firstParentIter{next: c, excluded: excluded}
+}
+
+func (it *firstParentIter) Next() (*object.Commit, error) {
+	c := it.next
+	if c == nil || it.excluded[c.Hash] {
+		return nil, io.EOF
+	}
+
+	it.next = nil
+	if c.NumParents() > 0 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		it.next = parent
+	}
+
+	return c, nil
+}
+
+func (it *firstParentIter) ForEach(cb func(*object.Commit) error) error {
+	for {
+		c, err := it.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := cb(c); err != nil {
+			if err == storer.ErrStop {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (it *firstParentIter) Close() {
+	it.next = nil
+}
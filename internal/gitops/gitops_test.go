@@ -0,0 +1,252 @@
+package gitops
+
+import (
+	"gotver/internal/gittest"
+	"reflect"
+	"testing"
+)
+
+func newGitOps(t *testing.T, repo *gittest.Repo) *GitOps {
+	t.Helper()
+
+	g := New()
+	if err := g.SetRepository(repo.Repository); err != nil {
+		t.Fatalf("set repository: %v", err)
+	}
+	return g
+}
+
+func TestGetLatestTag(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   string
+	}{
+		{
+			name:   "no tags",
+			script: "commit feat: initial",
+			want:   "",
+		},
+		{
+			name: "newest tag",
+			script: `
+				commit feat: initial
+				tag v1.0.0
+				commit fix: bug
+				tag v1.0.1
+				commit fix: another bug`,
+			want: "v1.0.1",
+		},
+		{
+			name: "lightweight tag",
+			script: `
+				commit feat: initial
+				tag v1.0.0
+				commit fix: bug
+				lightweight v1.0.1`,
+			want: "v1.0.1",
+		},
+		{
+			name: "tags of other branches are ignored",
+			script: `
+				commit feat: initial
+				tag v1.0.0
+				branch maintenance
+				commit fix: bug
+				checkout master
+				commit feat: new
+				tag v1.1.0
+				checkout maintenance`,
+			want: "v1.0.0",
+		},
+		{
+			name: "tags of merged branches are found",
+			script: `
+				commit feat: initial
+				tag v1.0.0
+				branch feature
+				commit feat: new
+				tag v1.1.0-rc.1
+				checkout master
+				merge feature`,
+			want: "v1.1.0-rc.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGitOps(t, gittest.New(t).Run(tt.script))
+
+			got, err := g.GetLatestTag()
+			if err != nil {
+				t.Fatalf("GetLatestTag() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetLatestTag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCommitsBetweenTags(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag v1.0.0
+		commit fix: first
+		commit fix: second
+		tag v1.0.1
+		branch feature
+		commit feat: branch
+		checkout master
+		commit fix: third
+		merge feature Merge pull request #1`)
+
+	tests := []struct {
+		name        string
+		start, end  string
+		firstParent bool
+		mergesOnly  bool
+		want        []string
+	}{
+		{
+			name:  "between tags",
+			start: "v1.0.1",
+			end:   "v1.0.0",
+			want:  []string{"fix: second", "fix: first"},
+		},
+		{
+			name: "from HEAD",
+			end:  "v1.0.1",
+			want: []string{"Merge pull request #1", "fix: third", "feat: branch"},
+		},
+		{
+			name:  "unknown start tag starts at HEAD",
+			start: "v9.9.9",
+			end:   "v1.0.1",
+			want:  []string{"Merge pull request #1", "fix: third", "feat: branch"},
+		},
+		{
+			name:  "whole history",
+			start: "v1.0.0",
+			want:  []string{"feat: initial"},
+		},
+		{
+			name:        "first parent",
+			end:         "v1.0.1",
+			firstParent: true,
+			want:        []string{"Merge pull request #1", "fix: third"},
+		},
+		{
+			name:       "merges only",
+			end:        "v1.0.1",
+			mergesOnly: true,
+			want:       []string{"Merge pull request #1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGitOps(t, repo)
+			g.SetFirstParent(tt.firstParent)
+			g.SetMergesOnly(tt.mergesOnly)
+
+			commits, err := g.GetCommitsBetweenTags(tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("GetCommitsBetweenTags() error = %v", err)
+			}
+			if got := gittest.Messages(commits); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetCommitsBetweenTags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPush(t *testing.T) {
+	repo := gittest.New(t).Run("commit feat: initial")
+	remote := repo.AddRemote("origin")
+	g := newGitOps(t, repo)
+
+	if err := g.CreateTag("v1.0.0", "release"); err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+	if err := g.Push(); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if got := remote.Branch(gittest.DefaultBranch); got != repo.Head() {
+		t.Errorf("remote branch = %s, want %s", got, repo.Head())
+	}
+
+	if err := g.PushTag("v1.0.0"); err != nil {
+		t.Fatalf("PushTag() error = %v", err)
+	}
+	if !remote.HasTag("v1.0.0") {
+		t.Error("tag was not pushed")
+	}
+
+	if err := g.PushTag("v1.0.0"); err != nil {
+		t.Errorf("PushTag() of a pushed tag error = %v", err)
+	}
+
+	remote.Disconnect()
+	if err := g.Push(); err == nil {
+		t.Error("Push() to a disconnected remote succeeded")
+	}
+}
+
+func TestGetUpstreamStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   UpstreamStatus
+	}{
+		{
+			name:   "no upstream",
+			script: "commit feat: initial",
+			want:   UpstreamStatus{Branch: "master"},
+		},
+		{
+			name: "up to date",
+			script: `
+				commit feat: initial
+				push origin`,
+			want: UpstreamStatus{Branch: "master", Upstream: "origin/master"},
+		},
+		{
+			name: "ahead",
+			script: `
+				commit feat: initial
+				push origin
+				commit fix: first
+				commit fix: second`,
+			want: UpstreamStatus{Branch: "master", Upstream: "origin/master", Ahead: 2},
+		},
+		{
+			name: "diverged",
+			script: `
+				commit feat: initial
+				commit feat: remote
+				push origin
+				reset HEAD~1
+				commit feat: local
+				commit fix: local`,
+			want: UpstreamStatus{Branch: "master", Upstream: "origin/master", Ahead: 2, Behind: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := gittest.New(t)
+			repo.AddRemote("origin")
+			repo.Run(tt.script)
+			g := newGitOps(t, repo)
+
+			got, err := g.GetUpstreamStatus()
+			if err != nil {
+				t.Fatalf("GetUpstreamStatus() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetUpstreamStatus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
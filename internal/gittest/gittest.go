@@ -0,0 +1,450 @@
+// Package gittest builds git repositories in memory for tests. A Repo is
+// described with a small DSL of commits, branches, merges and tags, either
+// by chaining methods or with a script:
+//
+//	repo := gittest.New(t).Run(`
+//		commit feat: initial
+//		tag v1.0.0
+//		branch feature
+//		commit fix: bug
+//		checkout master
+//		merge feature Merge pull request #1
+//	`)
+//
+// Commits and tags get strictly increasing timestamps, so the order of tags
+// is deterministic. Remotes are in memory as well, pushing to them works like
+// pushing to a real remote.
+package gittest
+
+import (
+	"fmt"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	// Protocol is the URL scheme of in-memory remotes.
+	Protocol = "memory"
+	// DefaultBranch is the branch a new Repo starts on.
+	DefaultBranch = "master"
+)
+
+// Epoch is the time of the first commit of every Repo.
+var Epoch = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// Signature is the author, committer and tagger of all objects.
+var Signature = object.Signature{Name: "gitver", Email: "gitver@example.com"}
+
+// Repo is a git repository with storage and worktree in memory.
+type Repo struct {
+	t          testing.TB
+	Repository *git.Repository
+	Worktree   *git.Worktree
+	Fs         billy.Filesystem
+	clock      time.Time
+	commits    int
+}
+
+// New returns an empty repository on the default branch. Errors fail the
+// test immediately.
+func New(t testing.TB) *Repo {
+	t.Helper()
+
+	fs := memfs.New()
+	r, err := git.Init(memory.NewStorage(), fs)
+	if err != nil {
+		t.Fatalf("init repository: %v", err)
+	}
+
+	w, err := r.Worktree()
+	if err != nil {
+		t.Fatalf("open worktree: %v", err)
+	}
+
+	return &Repo{t: t, Repository: r, Worktree: w, Fs: fs, clock: Epoch}
+}
+
+// Run executes a script with one statement per line. Empty lines and lines
+// starting with # are ignored. The statements are
+//
+//	commit <message>
+//	branch <name>
+//	checkout <name>
+//	merge <branch> [<message>]
+//	revert <revision>
+//	reset <revision>
+//	tag <name>
+//	lightweight <name>
+//	push <remote>
+//	file <path> <content>
+//
+// Literal \n in a commit message is replaced by a line break, so commits
+// can have a body, e.g. commit feat: api\n\nBREAKING CHANGE: removed.
+func (r *Repo) Run(script string) *Repo {
+	r.t.Helper()
+
+	for n, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		statement, argument, _ := strings.Cut(line, " ")
+		argument = strings.TrimSpace(argument)
+		switch statement {
+		case "commit":
+			r.Commit(strings.ReplaceAll(argument, `\n`, "\n"))
+		case "branch":
+			r.Branch(argument)
+		case "checkout":
+			r.Checkout(argument)
+		case "merge":
+			branch, message, _ := strings.Cut(argument, " ")
+			r.Merge(branch, strings.TrimSpace(message))
+		case "revert":
+			r.Revert(argument)
+		case "reset":
+			r.Reset(argument)
+		case "tag":
+			r.Tag(argument)
+		case "lightweight":
+			r.LightweightTag(argument)
+		case "push":
+			r.Push(argument)
+		case "file":
+			path, content, _ := strings.Cut(argument, " ")
+			r.WriteFile(path, content)
+		default:
+			r.t.Fatalf("script line %d: unknown statement %q", n+1, statement)
+		}
+	}
+
+	return r
+}
+
+// now advances the clock of the repository by a minute and returns it.
+func (r *Repo) now() time.Time {
+	r.clock = r.clock.Add(time.Minute)
+	return r.clock
+}
+
+func (r *Repo) signature() *object.Signature {
+	s := Signature
+	s.When = r.now()
+	return &s
+}
+
+// WriteFile writes a file to the worktree without staging it.
+func (r *Repo) WriteFile(path string, content string) *Repo {
+	r.t.Helper()
+
+	f, err := r.Fs.Create(path)
+	if err != nil {
+		r.t.Fatalf("create %s: %v", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write([]byte(content)); err != nil {
+		r.t.Fatalf("write %s: %v", path, err)
+	}
+	return r
+}
+
+// Commit stages all changes and commits them. Every commit changes a file of
+// its own, so commits are never empty.
+func (r *Repo) Commit(message string) *Repo {
+	r.t.Helper()
+	r.commit(message, nil)
+	return r
+}
+
+func (r *Repo) commit(message string, parents []plumbing.Hash) plumbing.Hash {
+	r.t.Helper()
+
+	r.commits++
+	r.WriteFile(fmt.Sprintf("commit-%d.txt", r.commits), message)
+
+	if err := r.Worktree.AddGlob("."); err != nil {
+		r.t.Fatalf("add: %v", err)
+	}
+
+	signature := r.signature()
+	hash, err := r.Worktree.Commit(message, &git.CommitOptions{
+		Author:    signature,
+		Committer: signature,
+		Parents:   parents,
+	})
+	if err != nil {
+		r.t.Fatalf("commit %q: %v", message, err)
+	}
+	return hash
+}
+
+// Branch creates a branch at HEAD and checks it out.
+func (r *Repo) Branch(name string) *Repo {
+	r.t.Helper()
+
+	if err := r.Worktree.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(name),
+		Create: true,
+	}); err != nil {
+		r.t.Fatalf("branch %s: %v", name, err)
+	}
+	return r
+}
+
+// Checkout checks out an existing branch.
+func (r *Repo) Checkout(name string) *Repo {
+	r.t.Helper()
+
+	if err := r.Worktree.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(name),
+	}); err != nil {
+		r.t.Fatalf("checkout %s: %v", name, err)
+	}
+	return r
+}
+
+// Merge creates a merge commit of HEAD and the branch. The merge commit
+// keeps the files of HEAD, which is all the analyzer looks at. Without a
+// message the merge commit is named like one created by git.
+func (r *Repo) Merge(branch string, message string) *Repo {
+	r.t.Helper()
+
+	ref, err := r.Repository.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err != nil {
+		r.t.Fatalf("merge %s: %v", branch, err)
+	}
+
+	if message == "" {
+		message = fmt.Sprintf("Merge branch '%s'", branch)
+	}
+
+	r.commit(message, []plumbing.Hash{r.Head(), ref.Hash()})
+	return r
+}
+
+// Revert commits a revert of the commit of the revision, e.g. HEAD~1, with
+// the message created by git revert.
+func (r *Repo) Revert(revision string) *Repo {
+	r.t.Helper()
+
+	c, err := r.Repository.CommitObject(r.Resolve(revision))
+	if err != nil {
+		r.t.Fatalf("revert %s: %v", revision, err)
+	}
+
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	r.Commit(fmt.Sprintf("Revert %q\n\nThis reverts commit %s.", subject, c.Hash))
+	return r
+}
+
+// Reset moves the HEAD branch to the commit of the revision, discarding the
+// commits and changes since, like git reset --hard.
+func (r *Repo) Reset(revision string) *Repo {
+	r.t.Helper()
+
+	if err := r.Worktree.Reset(&git.ResetOptions{Commit: r.Resolve(revision), Mode: git.HardReset}); err != nil {
+		r.t.Fatalf("reset %s: %v", revision, err)
+	}
+	return r
+}
+
+// Tag creates an annotated tag at HEAD.
+func (r *Repo) Tag(name string) *Repo {
+	r.t.Helper()
+
+	if _, err := r.Repository.CreateTag(name, r.Head(), &git.CreateTagOptions{
+		Tagger:  r.signature(),
+		Message: name,
+	}); err != nil {
+		r.t.Fatalf("tag %s: %v", name, err)
+	}
+	return r
+}
+
+// LightweightTag creates a lightweight tag at HEAD.
+func (r *Repo) LightweightTag(name string) *Repo {
+	r.t.Helper()
+
+	ref := plumbing.NewHashReference(plumbing.NewTagReferenceName(name), r.Head())
+	if err := r.Repository.Storer.SetReference(ref); err != nil {
+		r.t.Fatalf("tag %s: %v", name, err)
+	}
+	return r
+}
+
+// Head returns the commit HEAD points to.
+func (r *Repo) Head() plumbing.Hash {
+	r.t.Helper()
+
+	ref, err := r.Repository.Head()
+	if err != nil {
+		r.t.Fatalf("head: %v", err)
+	}
+	return ref.Hash()
+}
+
+// Resolve returns the commit of a revision, e.g. a tag or branch name.
+func (r *Repo) Resolve(revision string) plumbing.Hash {
+	r.t.Helper()
+
+	hash, err := r.Repository.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		r.t.Fatalf("resolve %s: %v", revision, err)
+	}
+	return *hash
+}
+
+// Messages returns the first lines of the commit messages, e.g. to compare
+// the result of a log query.
+func Messages(commits []*object.Commit) []string {
+	messages := make([]string, 0, len(commits))
+	for _, c := range commits {
+		first, _, _ := strings.Cut(c.Message, "\n")
+		messages = append(messages, first)
+	}
+	return messages
+}
+
+// Remote is a bare repository in memory, reachable through the memory
+// protocol.
+type Remote struct {
+	t          testing.TB
+	URL        string
+	Repository *git.Repository
+	endpoint   string
+}
+
+// AddRemote creates an empty remote and adds it to the repository under the
+// name. It is removed when the test ends.
+func (r *Repo) AddRemote(name string) *Remote {
+	r.t.Helper()
+
+	url := fmt.Sprintf("%s:///%s/%s", Protocol, strings.ReplaceAll(r.t.Name(), "/", "-"), name)
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		r.t.Fatalf("remote %s: %v", name, err)
+	}
+
+	storage := memory.NewStorage()
+	bare, err := git.Init(storage, nil)
+	if err != nil {
+		r.t.Fatalf("remote %s: %v", name, err)
+	}
+
+	remote := &Remote{t: r.t, URL: url, Repository: bare, endpoint: endpoint.String()}
+	remotes.add(remote.endpoint, storage)
+	r.t.Cleanup(remote.Disconnect)
+
+	if _, err := r.Repository.CreateRemote(&config.RemoteConfig{Name: name, URLs: []string{url}}); err != nil {
+		r.t.Fatalf("remote %s: %v", name, err)
+	}
+	return remote
+}
+
+// Push pushes the HEAD branch to the remote and makes the pushed branch its
+// upstream branch, like git push --set-upstream.
+func (r *Repo) Push(remote string) *Repo {
+	r.t.Helper()
+
+	head, err := r.Repository.Head()
+	if err != nil {
+		r.t.Fatalf("push: %v", err)
+	}
+	branch := head.Name()
+
+	err = r.Repository.Push(&git.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   []config.RefSpec{config.RefSpec(branch + ":" + branch)},
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		r.t.Fatalf("push %s: %v", remote, err)
+	}
+
+	tracking := plumbing.NewHashReference(plumbing.NewRemoteReferenceName(remote, branch.Short()), head.Hash())
+	if err := r.Repository.Storer.SetReference(tracking); err != nil {
+		r.t.Fatalf("push %s: %v", remote, err)
+	}
+
+	cfg, err := r.Repository.Config()
+	if err != nil {
+		r.t.Fatalf("push %s: %v", remote, err)
+	}
+	cfg.Branches[branch.Short()] = &config.Branch{Name: branch.Short(), Remote: remote, Merge: branch}
+	if err := r.Repository.SetConfig(cfg); err != nil {
+		r.t.Fatalf("push %s: %v", remote, err)
+	}
+	return r
+}
+
+// Disconnect makes the remote unreachable, so pushing to it fails.
+func (r *Remote) Disconnect() {
+	remotes.remove(r.endpoint)
+}
+
+// Branch returns the commit the branch points to on the remote, or the zero
+// hash if the branch was not pushed.
+func (r *Remote) Branch(name string) plumbing.Hash {
+	ref, err := r.Repository.Reference(plumbing.NewBranchReferenceName(name), true)
+	if err != nil {
+		return plumbing.ZeroHash
+	}
+	return ref.Hash()
+}
+
+// HasTag reports whether the tag was pushed to the remote.
+func (r *Remote) HasTag(name string) bool {
+	_, err := r.Repository.Tag(name)
+	return err == nil
+}
+
+// remotes holds the storages of all in-memory remotes by endpoint.
+var remotes = &loader{storages: map[string]storer.Storer{}}
+
+type loader struct {
+	once     sync.Once
+	mu       sync.Mutex
+	storages map[string]storer.Storer
+}
+
+func (l *loader) add(endpoint string, s storer.Storer) {
+	l.once.Do(func() {
+		client.InstallProtocol(Protocol, server.NewServer(l))
+	})
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.storages[endpoint] = s
+}
+
+func (l *loader) remove(endpoint string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.storages, endpoint)
+}
+
+// Load implements server.Loader.
+func (l *loader) Load(ep *transport.Endpoint) (storer.Storer, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, ok := l.storages[ep.String()]
+	if !ok {
+		return nil, transport.ErrRepositoryNotFound
+	}
+	return s, nil
+}
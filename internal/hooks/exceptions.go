@@ -0,0 +1,51 @@
+package hooks
+
+import (
+	"fmt"
+	"gotver/internal/exceptions"
+)
+
+type UnknownHookError string
+
+func (p UnknownHookError) Error() string {
+	return fmt.Sprintf("error code: %d - unknown hook %q", exceptions.UnknownHook, string(p))
+}
+
+func (p UnknownHookError) Code() exceptions.Code {
+	return exceptions.UnknownHook
+}
+
+func (p UnknownHookError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.UnknownHook, nil)
+}
+
+type HookExistsError string
+
+func (p HookExistsError) Error() string {
+	return fmt.Sprintf("error code: %d - hook %q already exists", exceptions.HookExists, string(p))
+}
+
+func (p HookExistsError) Code() exceptions.Code {
+	return exceptions.HookExists
+}
+
+func (p HookExistsError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.HookExists, nil)
+}
+
+type HookWriteError struct {
+	hook  string
+	error error
+}
+
+func (p HookWriteError) Error() string {
+	return fmt.Sprintf("error code: %d - hook %q could not be written: %v", exceptions.HookWrite, p.hook, p.error)
+}
+
+func (p HookWriteError) Code() exceptions.Code {
+	return exceptions.HookWrite
+}
+
+func (p HookWriteError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.HookWrite, p.error)
+}
@@ -0,0 +1,99 @@
+package hooks
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	CommitMsg = "commit-msg"
+	PrePush   = "pre-push"
+
+	// marker identifies hooks written by gitver.
+	marker = "# installed by gitver"
+
+	// localSuffix is appended to existing hooks that are preserved and
+	// chained from the gitver hook.
+	localSuffix = ".local"
+)
+
+const commitMsgScript = `#!/bin/sh
+{{marker}}
+hook_dir=$(dirname "$0")
+if [ -x "$hook_dir/{{local}}" ]; then
+	"$hook_dir/{{local}}" "$@" || exit $?
+fi
+{{program}} lint --file "$1"
+`
+
+const prePushScript = `#!/bin/sh
+{{marker}}
+# stdin can only be read once, keep it for the chained hook.
+input=$(cat)
+hook_dir=$(dirname "$0")
+if [ -x "$hook_dir/{{local}}" ]; then
+	printf '%s\n' "$input" | "$hook_dir/{{local}}" "$@" || exit $?
+fi
+zero=0000000000000000000000000000000000000000
+printf '%s\n' "$input" | while read -r local_ref local_sha remote_ref remote_sha; do
+	# Deleted branches have nothing to lint, new branches are linted on commit.
+	if [ -z "$local_sha" ] || [ "$local_sha" = "$zero" ] || [ "$remote_sha" = "$zero" ]; then
+		continue
+	fi
+	{{program}} lint --range "$remote_sha..$local_sha" || exit 1
+done
+`
+
+// Script returns the hook script for the hook name.
+func Script(name string, program string) (string, error) {
+	replacer := strings.NewReplacer("{{marker}}", marker, "{{local}}", name+localSuffix, "{{program}}", program)
+
+	switch name {
+	case CommitMsg:
+		return replacer.Replace(commitMsgScript), nil
+	case PrePush:
+		return replacer.Replace(prePushScript), nil
+	default:
+		return "", UnknownHookError(name)
+	}
+}
+
+// Install writes the hook into the hooks directory. An existing hook that
+// was not written by gitver is preserved as <name>.local and invoked by the
+// new hook. It returns true if an existing hook was preserved.
+func Install(dir string, name string, program string) (bool, error) {
+	script, err := Script(name, program)
+	if err != nil {
+		return false, err
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return false, HookWriteError{name, err}
+	}
+
+	path := filepath.Join(dir, name)
+	preserved := false
+
+	existing, err := os.ReadFile(path)
+	switch {
+	case err == nil && !bytes.Contains(existing, []byte(marker)):
+		local := path + localSuffix
+		if _, err := os.Stat(local); err == nil {
+			return false, HookExistsError(local)
+		}
+		if err := os.Rename(path, local); err != nil {
+			return false, HookWriteError{name, err}
+		}
+		preserved = true
+	case err != nil && !os.IsNotExist(err):
+		return false, HookWriteError{name, err}
+	}
+
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		return preserved, HookWriteError{name, err}
+	}
+
+	return preserved, nil
+}
@@ -0,0 +1,42 @@
+package lifecycle
+
+import (
+	"fmt"
+	"gotver/internal/exceptions"
+)
+
+type UnknownEventError string
+
+func (p UnknownEventError) Error() string {
+	return fmt.Sprintf("error code: %d - unknown lifecycle hook %q", exceptions.UnknownEvent, string(p))
+}
+
+func (p UnknownEventError) Code() exceptions.Code {
+	return exceptions.UnknownEvent
+}
+
+func (p UnknownEventError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.UnknownEvent, nil)
+}
+
+type HookFailedError struct {
+	event   string
+	command string
+	error   error
+	output  string
+}
+
+func (p HookFailedError) Error() string {
+	if p.output == "" {
+		return fmt.Sprintf("error code: %d - %s hook %q failed: %v", exceptions.HookFailed, p.event, p.command, p.error)
+	}
+	return fmt.Sprintf("error code: %d - %s hook %q failed: %v\n%s", exceptions.HookFailed, p.event, p.command, p.error, p.output)
+}
+
+func (p HookFailedError) Code() exceptions.Code {
+	return exceptions.HookFailed
+}
+
+func (p HookFailedError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.HookFailed, p.error)
+}
@@ -0,0 +1,121 @@
+package lifecycle
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+)
+
+const (
+	PreBump          = "pre-bump"
+	PostVersionWrite = "post-version-write"
+	PreCommit        = "pre-commit"
+	PostTag          = "post-tag"
+	PostPush         = "post-push"
+)
+
+const (
+	EnvOldVersion = "GITVER_OLD_VERSION"
+	EnvNewVersion = "GITVER_NEW_VERSION"
+	EnvTag        = "GITVER_TAG"
+	EnvComponent  = "GITVER_COMPONENT"
+	EnvHook       = "GITVER_HOOK"
+)
+
+// Events are the lifecycle events in the order they occur during a bump.
+var Events = []string{PreBump, PostVersionWrite, PreCommit, PostTag, PostPush}
+
+// Runner executes the commands configured for lifecycle events.
+type Runner struct {
+	hooks  map[string][]string
+	dir    string
+	env    map[string]string
+	output io.Writer
+}
+
+// New creates a runner for the configured hooks. Commands are executed in
+// dir. Unknown events are reported as errors.
+func New(hooks map[string][]string, dir string) (*Runner, error) {
+	for event := range hooks {
+		if !isEvent(event) {
+			return nil, UnknownEventError(event)
+		}
+	}
+
+	return &Runner{
+		hooks: hooks,
+		dir:   dir,
+		env:   make(map[string]string),
+	}, nil
+}
+
+// SetEnv sets an environment variable for all following commands.
+func (r *Runner) SetEnv(key, value string) {
+	r.env[key] = value
+}
+
+// SetOutput sets the writer receiving the output of the commands. Without
+// a writer the output is only part of the error of failing commands.
+func (r *Runner) SetOutput(output io.Writer) {
+	r.output = output
+}
+
+// Run executes the commands of the event in order and stops at the first
+// command exiting with a non-zero code.
+func (r *Runner) Run(event string) error {
+	for _, command := range r.hooks[event] {
+		var output bytes.Buffer
+
+		cmd := shell(command)
+		cmd.Dir = r.dir
+		cmd.Env = append(os.Environ(), r.environ(event)...)
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+
+		err := cmd.Run()
+
+		if r.output != nil && output.Len() > 0 {
+			fmt.Fprintf(r.output, "[%s] %s\n%s", event, command, output.String())
+		}
+
+		if err != nil {
+			return HookFailedError{event, command, err, output.String()}
+		}
+	}
+
+	return nil
+}
+
+func (r *Runner) environ(event string) []string {
+	keys := make([]string, 0, len(r.env))
+	for key := range r.env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	env := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		env = append(env, key+"="+r.env[key])
+	}
+	return append(env, EnvHook+"="+event)
+}
+
+func shell(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+func isEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package lifecycle
+
+import (
+	"bytes"
+	"errors"
+	"gotver/internal/exceptions"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// skipWithoutShell skips tests whose hooks are POSIX shell commands.
+func skipWithoutShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks of the tests are POSIX shell commands")
+	}
+}
+
+func TestNewUnknownEvent(t *testing.T) {
+	_, err := New(map[string][]string{"pre-release": {"true"}}, t.TempDir())
+	if !errors.Is(err, UnknownEventError("pre-release")) || !errors.Is(err, exceptions.ErrInvalidConfig) {
+		t.Errorf("New() error = %v, want an unknown event error", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	skipWithoutShell(t)
+
+	dir := t.TempDir()
+	r, err := New(map[string][]string{
+		PreCommit: {"echo first >> log.txt", "echo second >> log.txt"},
+		PostTag:   {"echo tag >> log.txt"},
+	}, dir)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := r.Run(PreCommit); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if err := r.Run(PostPush); err != nil {
+		t.Fatalf("Run() of an event without hooks error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "log.txt"))
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	if got := string(data); got != "first\nsecond\n" {
+		t.Errorf("hooks wrote %q, want the pre-commit hooks in order", got)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	skipWithoutShell(t)
+
+	dir := t.TempDir()
+	r, err := New(map[string][]string{
+		PreCommit: {"echo checking", "echo broken >&2; exit 3", "touch never.txt"},
+	}, dir)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	var output bytes.Buffer
+	r.SetOutput(&output)
+
+	err = r.Run(PreCommit)
+	if !errors.Is(err, exceptions.ErrHook) || exceptions.CodeOf(err) != exceptions.HookFailed {
+		t.Fatalf("Run() error = %v, want a failed hook", err)
+	}
+	if !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Run() error = %q, want the output of the hook", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "never.txt")); !os.IsNotExist(err) {
+		t.Error("hooks after the failed hook were run")
+	}
+	if want := "[pre-commit] echo checking\nchecking\n"; !strings.HasPrefix(output.String(), want) {
+		t.Errorf("output = %q, want it to start with %q", output.String(), want)
+	}
+}
+
+func TestRunEnv(t *testing.T) {
+	skipWithoutShell(t)
+
+	dir := t.TempDir()
+	r, err := New(map[string][]string{
+		PostTag: {`echo "$GITVER_OLD_VERSION $GITVER_NEW_VERSION $GITVER_TAG $GITVER_COMPONENT $GITVER_HOOK" > env.txt`},
+	}, dir)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	r.SetEnv(EnvOldVersion, "1.0.0")
+	r.SetEnv(EnvNewVersion, "1.1.0")
+	r.SetEnv(EnvTag, "api/v1.1.0")
+	r.SetEnv(EnvComponent, "api")
+
+	if err := r.Run(PostTag); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "env.txt"))
+	if err != nil {
+		t.Fatalf("read env: %v", err)
+	}
+	if got, want := string(data), "1.0.0 1.1.0 api/v1.1.0 api post-tag\n"; got != want {
+		t.Errorf("hook environment = %q, want %q", got, want)
+	}
+}
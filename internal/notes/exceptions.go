@@ -0,0 +1,37 @@
+package notes
+
+import (
+	"fmt"
+	"gotver/internal/exceptions"
+)
+
+type UnknownFormatError string
+
+func (p UnknownFormatError) Error() string {
+	return fmt.Sprintf("error code: %d - unknown release notes format %q", exceptions.UnknownFormat, string(p))
+}
+
+func (p UnknownFormatError) Code() exceptions.Code {
+	return exceptions.UnknownFormat
+}
+
+func (p UnknownFormatError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.UnknownFormat, nil)
+}
+
+type RenderError struct {
+	format string
+	error  error
+}
+
+func (p RenderError) Error() string {
+	return fmt.Sprintf("error code: %d - %s release notes could not be rendered: %v", exceptions.Render, p.format, p.error)
+}
+
+func (p RenderError) Code() exceptions.Code {
+	return exceptions.Render
+}
+
+func (p RenderError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.Render, p.error)
+}
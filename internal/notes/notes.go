@@ -0,0 +1,108 @@
+package notes
+
+import (
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"gotver/internal/analyzer"
+	"time"
+)
+
+const (
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+	FormatText     = "text"
+)
+
+// sectionTitles are the titles of the commit types in the order they are
+// rendered. Types without a title are not part of the release notes.
+var sectionTitles = []struct {
+	Type  string
+	Title string
+}{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance Improvements"},
+	{"revert", "Reverts"},
+	{"refactor", "Code Refactoring"},
+	{"docs", "Documentation"},
+	{"build", "Build System"},
+	{"ci", "Continuous Integration"},
+	{"test", "Tests"},
+	{"style", "Styles"},
+	{"chore", "Miscellaneous Chores"},
+}
+
+// Notes are the release notes of a single version.
+type Notes struct {
+	Version         string    `json:"version"`
+	PreviousVersion string    `json:"previousVersion,omitempty"`
+	Tag             string    `json:"tag,omitempty"`
+	PreviousTag     string    `json:"previousTag,omitempty"`
+	Date            time.Time `json:"date"`
+	Breaking        []Entry   `json:"breaking"`
+	Sections        []Section `json:"sections"`
+}
+
+// Section groups the entries of one commit type.
+type Section struct {
+	Type    string  `json:"type"`
+	Title   string  `json:"title"`
+	Entries []Entry `json:"entries"`
+}
+
+// Entry is a single commit of the release notes.
+type Entry struct {
+	Hash        string `json:"hash"`
+	ShortHash   string `json:"shortHash"`
+	Type        string `json:"type"`
+	Scope       string `json:"scope,omitempty"`
+	Description string `json:"description"`
+	Body        string `json:"body,omitempty"`
+	Breaking    bool   `json:"breaking"`
+	Author      string `json:"author"`
+}
+
+// Build categorises the commits of a version. Reverted commits and commits
+// not following Conventional Commits are left out.
+func Build(version string, commits []*object.Commit) Notes {
+	n := Notes{Version: version, Date: time.Now(), Breaking: []Entry{}, Sections: []Section{}}
+	if len(commits) > 0 {
+		n.Date = commits[0].Committer.When
+	}
+
+	entries := make(map[string][]Entry)
+	for _, c := range analyzer.FilterReverted(commits) {
+		message, ok := analyzer.ParseMessage(c.Message)
+		if !ok {
+			continue
+		}
+
+		entry := Entry{
+			Hash:        c.Hash.String(),
+			ShortHash:   c.Hash.String()[:7],
+			Type:        message.Type,
+			Scope:       message.Scope,
+			Description: message.Description,
+			Body:        message.Body,
+			Breaking:    message.Breaking,
+			Author:      c.Author.Name,
+		}
+
+		if entry.Breaking {
+			n.Breaking = append(n.Breaking, entry)
+		}
+		entries[entry.Type] = append(entries[entry.Type], entry)
+	}
+
+	for _, section := range sectionTitles {
+		if len(entries[section.Type]) == 0 {
+			continue
+		}
+		n.Sections = append(n.Sections, Section{
+			Type:    section.Type,
+			Title:   section.Title,
+			Entries: entries[section.Type],
+		})
+	}
+
+	return n
+}
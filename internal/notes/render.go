@@ -0,0 +1,85 @@
+package notes
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"text/template"
+)
+
+const markdownTemplate = `## {{.Version}} ({{.Date.Format "2006-01-02"}})
+{{- if .Breaking}}
+
+### BREAKING CHANGES
+{{range .Breaking}}
+* {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}} ({{.ShortHash}})
+{{- end}}
+{{- end}}
+{{- range .Sections}}
+
+### {{.Title}}
+{{range .Entries}}
+* {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}} ({{.ShortHash}})
+{{- end}}
+{{- end}}
+`
+
+const textTemplate = `{{.Version}} ({{.Date.Format "2006-01-02"}})
+{{- if .Breaking}}
+
+BREAKING CHANGES
+{{range .Breaking}}
+  - {{if .Scope}}{{.Scope}}: {{end}}{{.Description}} ({{.ShortHash}})
+{{- end}}
+{{- end}}
+{{- range .Sections}}
+
+{{.Title}}
+{{range .Entries}}
+  - {{if .Scope}}{{.Scope}}: {{end}}{{.Description}} ({{.ShortHash}})
+{{- end}}
+{{- end}}
+`
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// Render renders the notes in the format. A non-empty custom template
+// replaces the built-in template of the format.
+func Render(n Notes, format string, custom string) (string, error) {
+	text := custom
+	if text == "" {
+		switch format {
+		case FormatMarkdown:
+			text = markdownTemplate
+		case FormatText:
+			text = textTemplate
+		case FormatJSON:
+			data, err := json.MarshalIndent(n, "", "  ")
+			if err != nil {
+				return "", RenderError{format, err}
+			}
+			return string(data) + "\n", nil
+		default:
+			return "", UnknownFormatError(format)
+		}
+	}
+
+	tmpl, err := template.New(format).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", RenderError{format, err}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, n); err != nil {
+		return "", RenderError{format, err}
+	}
+
+	return buf.String(), nil
+}
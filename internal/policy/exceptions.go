@@ -0,0 +1,62 @@
+package policy
+
+import (
+	"fmt"
+	"gotver/internal/exceptions"
+)
+
+type NoMatchingPolicyError string
+
+func (p NoMatchingPolicyError) Error() string {
+	return fmt.Sprintf("error code: %d - no branch policy matches branch %q", exceptions.NoMatchingPolicy, string(p))
+}
+
+func (p NoMatchingPolicyError) Code() exceptions.Code {
+	return exceptions.NoMatchingPolicy
+}
+
+func (p NoMatchingPolicyError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.NoMatchingPolicy, nil)
+}
+
+type InvalidPatternError string
+
+func (p InvalidPatternError) Error() string {
+	return fmt.Sprintf("error code: %d - invalid branch pattern %q", exceptions.InvalidPattern, string(p))
+}
+
+func (p InvalidPatternError) Code() exceptions.Code {
+	return exceptions.InvalidPattern
+}
+
+func (p InvalidPatternError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.InvalidPattern, nil)
+}
+
+type InvalidPolicyError string
+
+func (p InvalidPolicyError) Error() string {
+	return fmt.Sprintf("error code: %d - invalid branch policy for pattern %q", exceptions.InvalidPolicy, string(p))
+}
+
+func (p InvalidPolicyError) Code() exceptions.Code {
+	return exceptions.InvalidPolicy
+}
+
+func (p InvalidPolicyError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.InvalidPolicy, nil)
+}
+
+type BranchNotAllowedError string
+
+func (p BranchNotAllowedError) Error() string {
+	return fmt.Sprintf("error code: %d - versioning is not allowed on branch %q", exceptions.BranchNotAllowed, string(p))
+}
+
+func (p BranchNotAllowedError) Code() exceptions.Code {
+	return exceptions.BranchNotAllowed
+}
+
+func (p BranchNotAllowedError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.BranchNotAllowed, nil)
+}
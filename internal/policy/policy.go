@@ -0,0 +1,92 @@
+package policy
+
+import (
+	"path"
+)
+
+const (
+	TypeRelease    = "release"
+	TypePreRelease = "prerelease"
+	TypeDeny       = "deny"
+)
+
+// Policy maps a branch name pattern to the versioning behaviour allowed on
+// matching branches.
+type Policy struct {
+	Pattern    string `mapstructure:"pattern" desc:"Branch name pattern in path.Match syntax"`
+	Type       string `mapstructure:"type" desc:"Versioning allowed on matching branches"`
+	PreRelease string `mapstructure:"prerelease" desc:"Pre-release identifier of prerelease branches"`
+	MaxBump    string `mapstructure:"maxBump" desc:"Most significant segment that may be bumped"`
+	// Maintenance marks branches named after a version line, e.g.
+	// `release/1.4` or `support/2.x`, on which bumps must stay in that line.
+	Maintenance bool `mapstructure:"maintenance" desc:"Branches are named after the version line they maintain"`
+}
+
+// Match returns the first policy whose pattern matches the branch. Patterns
+// use path.Match syntax, so `release/*` matches `release/1.4`.
+func Match(policies []Policy, branch string) (Policy, error) {
+	for _, p := range policies {
+		if err := p.Validate(); err != nil {
+			return Policy{}, err
+		}
+
+		matched, err := path.Match(p.Pattern, branch)
+		if err != nil {
+			return Policy{}, InvalidPatternError(p.Pattern)
+		}
+		if matched {
+			return p, nil
+		}
+	}
+
+	return Policy{}, NoMatchingPolicyError(branch)
+}
+
+// Validate checks that the policy has a pattern and a known type.
+func (p Policy) Validate() error {
+	if p.Pattern == "" {
+		return InvalidPatternError(p.Pattern)
+	}
+
+	switch p.MaxBump {
+	case "", "major", "minor", "patch":
+	default:
+		return InvalidPolicyError(p.Pattern)
+	}
+
+	switch p.Type {
+	case TypeRelease, TypeDeny:
+		return nil
+	case TypePreRelease:
+		if p.PreRelease == "" {
+			return InvalidPolicyError(p.Pattern)
+		}
+		return nil
+	default:
+		return InvalidPolicyError(p.Pattern)
+	}
+}
+
+// AllowsBump reports whether versions may be bumped on the branch.
+func (p Policy) AllowsBump() bool {
+	return p.Type != TypeDeny
+}
+
+// PreReleaseIdentifier returns the pre-release identifier for bumps on the
+// branch, or an empty string for final releases.
+func (p Policy) PreReleaseIdentifier() string {
+	if p.Type == TypePreRelease {
+		return p.PreRelease
+	}
+	return ""
+}
+
+// VersionLine returns the maintenance line encoded in the last segment of
+// the branch name, or an empty string if the policy is not a maintenance
+// policy.
+func (p Policy) VersionLine(branch string) string {
+	if !p.Maintenance {
+		return ""
+	}
+	return path.Base(branch)
+}
@@ -0,0 +1,74 @@
+package publish
+
+import (
+	"fmt"
+	"gotver/internal/exceptions"
+)
+
+type InvalidConfigError string
+
+func (p InvalidConfigError) Error() string {
+	return fmt.Sprintf("error code: %d - invalid publish configuration: %s", exceptions.PublishConfig, string(p))
+}
+
+func (p InvalidConfigError) Code() exceptions.Code {
+	return exceptions.PublishConfig
+}
+
+func (p InvalidConfigError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.PublishConfig, nil)
+}
+
+type RequestError struct {
+	method string
+	url    string
+	error  error
+}
+
+func (p RequestError) Error() string {
+	return fmt.Sprintf("error code: %d - %s %s failed: %v", exceptions.PublishRequest, p.method, p.url, p.error)
+}
+
+func (p RequestError) Code() exceptions.Code {
+	return exceptions.PublishRequest
+}
+
+func (p RequestError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.PublishRequest, p.error)
+}
+
+type APIError struct {
+	method string
+	url    string
+	status int
+	body   string
+}
+
+func (p APIError) Error() string {
+	return fmt.Sprintf("error code: %d - %s %s returned %d: %s", exceptions.PublishAPI, p.method, p.url, p.status, p.body)
+}
+
+func (p APIError) Code() exceptions.Code {
+	return exceptions.PublishAPI
+}
+
+func (p APIError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.PublishAPI, nil)
+}
+
+type AssetError struct {
+	file  string
+	error error
+}
+
+func (p AssetError) Error() string {
+	return fmt.Sprintf("error code: %d - asset %q could not be uploaded: %v", exceptions.PublishAsset, p.file, p.error)
+}
+
+func (p AssetError) Code() exceptions.Code {
+	return exceptions.PublishAsset
+}
+
+func (p AssetError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.PublishAsset, p.error)
+}
@@ -0,0 +1,108 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// gitea publishes releases through the Gitea (and Forgejo) REST API.
+type gitea struct {
+	api        apiClient
+	repository string
+}
+
+type giteaRelease struct {
+	ID      int64  `json:"id"`
+	HTMLURL string `json:"html_url"`
+	Assets  []struct {
+		Name string `json:"name"`
+	} `json:"assets"`
+}
+
+type giteaReleaseRequest struct {
+	TagName    string `json:"tag_name,omitempty"`
+	Name       string `json:"name"`
+	Body       string `json:"body"`
+	PreRelease bool   `json:"prerelease"`
+}
+
+func (g *gitea) Publish(ctx context.Context, release Release) (Result, error) {
+	var existing giteaRelease
+	found, err := g.api.find(ctx, fmt.Sprintf("/repos/%s/releases/tags/%s", g.repository, url.PathEscape(release.Tag)), &existing)
+	if err != nil {
+		return Result{}, err
+	}
+
+	request := giteaReleaseRequest{Name: release.Name, Body: release.Notes, PreRelease: release.PreRelease}
+	result := Result{}
+
+	var published giteaRelease
+	if !found {
+		request.TagName = release.Tag
+		if err := g.api.doJSON(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/releases", g.repository), request, &published); err != nil {
+			return Result{}, err
+		}
+		result.Created = true
+	} else {
+		if err := g.api.doJSON(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/releases/%d", g.repository, existing.ID), request, &published); err != nil {
+			return Result{}, err
+		}
+		published.Assets = existing.Assets
+	}
+	result.URL = published.HTMLURL
+
+	var names []string
+	for _, asset := range published.Assets {
+		names = append(names, asset.Name)
+	}
+
+	for _, file := range release.Assets {
+		name := assetName(file)
+		if contains(names, name) {
+			result.SkippedAssets = append(result.SkippedAssets, name)
+			continue
+		}
+
+		body, contentType, err := multipartFile("attachment", file)
+		if err != nil {
+			return result, AssetError{file, err}
+		}
+
+		uploadURL := fmt.Sprintf("/repos/%s/releases/%d/assets?name=%s", g.repository, published.ID, url.QueryEscape(name))
+		if err := g.api.do(ctx, http.MethodPost, uploadURL, body, contentType, nil); err != nil {
+			return result, AssetError{file, err}
+		}
+		result.UploadedAssets = append(result.UploadedAssets, name)
+	}
+
+	return result, nil
+}
+
+// multipartFile encodes a file as multipart form field.
+func multipartFile(field string, file string) (io.Reader, string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, assetName(file))
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, "", err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return &body, writer.FormDataContentType(), nil
+}
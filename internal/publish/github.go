@@ -0,0 +1,93 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// gitHub publishes releases through the GitHub REST API.
+type gitHub struct {
+	api        apiClient
+	repository string
+}
+
+type gitHubRelease struct {
+	ID        int64  `json:"id"`
+	HTMLURL   string `json:"html_url"`
+	UploadURL string `json:"upload_url"`
+	Assets    []struct {
+		Name string `json:"name"`
+	} `json:"assets"`
+}
+
+type gitHubReleaseRequest struct {
+	TagName    string `json:"tag_name,omitempty"`
+	Name       string `json:"name"`
+	Body       string `json:"body"`
+	PreRelease bool   `json:"prerelease"`
+}
+
+func (g *gitHub) Publish(ctx context.Context, release Release) (Result, error) {
+	var existing gitHubRelease
+	found, err := g.api.find(ctx, fmt.Sprintf("/repos/%s/releases/tags/%s", g.repository, url.PathEscape(release.Tag)), &existing)
+	if err != nil {
+		return Result{}, err
+	}
+
+	request := gitHubReleaseRequest{Name: release.Name, Body: release.Notes, PreRelease: release.PreRelease}
+	result := Result{}
+
+	var published gitHubRelease
+	if !found {
+		request.TagName = release.Tag
+		if err := g.api.doJSON(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/releases", g.repository), request, &published); err != nil {
+			return Result{}, err
+		}
+		result.Created = true
+	} else {
+		if err := g.api.doJSON(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/releases/%d", g.repository, existing.ID), request, &published); err != nil {
+			return Result{}, err
+		}
+		published.Assets = existing.Assets
+	}
+	result.URL = published.HTMLURL
+
+	var names []string
+	for _, asset := range published.Assets {
+		names = append(names, asset.Name)
+	}
+
+	// The upload URL is an RFC 6570 template like .../assets{?name,label}
+	uploadURL, _, _ := strings.Cut(published.UploadURL, "{")
+	if uploadURL == "" {
+		uploadURL = fmt.Sprintf("%s/repos/%s/releases/%d/assets", g.api.baseURL, g.repository, published.ID)
+	}
+
+	for _, file := range release.Assets {
+		name := assetName(file)
+		if contains(names, name) {
+			result.SkippedAssets = append(result.SkippedAssets, name)
+			continue
+		}
+
+		// GitHub requires a Content-Length, which the request only gets
+		// from a sized reader.
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return result, AssetError{file, err}
+		}
+
+		err = g.api.do(ctx, http.MethodPost, uploadURL+"?name="+url.QueryEscape(name), bytes.NewReader(data), "application/octet-stream", nil)
+		if err != nil {
+			return result, AssetError{file, err}
+		}
+		result.UploadedAssets = append(result.UploadedAssets, name)
+	}
+
+	return result, nil
+}
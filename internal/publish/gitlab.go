@@ -0,0 +1,103 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// gitLab publishes releases through the GitLab REST API. Assets are
+// uploaded to the project and linked from the release.
+type gitLab struct {
+	api     apiClient
+	webURL  string
+	project string
+}
+
+type gitLabRelease struct {
+	TagName string `json:"tag_name"`
+	Links   struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+	Assets struct {
+		Links []struct {
+			Name string `json:"name"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+type gitLabReleaseRequest struct {
+	TagName     string `json:"tag_name,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type gitLabUpload struct {
+	FullPath string `json:"full_path"`
+}
+
+type gitLabLinkRequest struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func (g *gitLab) Publish(ctx context.Context, release Release) (Result, error) {
+	project := url.PathEscape(g.project)
+	releaseURL := fmt.Sprintf("/projects/%s/releases/%s", project, url.PathEscape(release.Tag))
+
+	var existing gitLabRelease
+	found, err := g.api.find(ctx, releaseURL, &existing)
+	if err != nil {
+		return Result{}, err
+	}
+
+	request := gitLabReleaseRequest{Name: release.Name, Description: release.Notes}
+	result := Result{}
+
+	var published gitLabRelease
+	if !found {
+		request.TagName = release.Tag
+		if err := g.api.doJSON(ctx, http.MethodPost, fmt.Sprintf("/projects/%s/releases", project), request, &published); err != nil {
+			return Result{}, err
+		}
+		result.Created = true
+	} else {
+		if err := g.api.doJSON(ctx, http.MethodPut, releaseURL, request, &published); err != nil {
+			return Result{}, err
+		}
+		published.Assets = existing.Assets
+	}
+	result.URL = published.Links.Self
+
+	var names []string
+	for _, link := range published.Assets.Links {
+		names = append(names, link.Name)
+	}
+
+	for _, file := range release.Assets {
+		name := assetName(file)
+		if contains(names, name) {
+			result.SkippedAssets = append(result.SkippedAssets, name)
+			continue
+		}
+
+		body, contentType, err := multipartFile("file", file)
+		if err != nil {
+			return result, AssetError{file, err}
+		}
+
+		var upload gitLabUpload
+		if err := g.api.do(ctx, http.MethodPost, fmt.Sprintf("/projects/%s/uploads", project), body, contentType, &upload); err != nil {
+			return result, AssetError{file, err}
+		}
+
+		link := gitLabLinkRequest{Name: name, URL: g.webURL + upload.FullPath}
+		if err := g.api.doJSON(ctx, http.MethodPost, releaseURL+"/assets/links", link, nil); err != nil {
+			return result, AssetError{file, err}
+		}
+		result.UploadedAssets = append(result.UploadedAssets, name)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,192 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+	ProviderGitea  = "gitea"
+
+	DefaultGitHubURL = "https://api.github.com"
+	DefaultTokenEnv  = "GITVER_TOKEN"
+)
+
+// Config configures the forge releases are published to.
+type Config struct {
+	Provider string `mapstructure:"provider" desc:"Forge releases are published to"`
+	// BaseURL is the API root for GitHub (e.g. https://host/api/v3 for
+	// GitHub Enterprise) and the server root for GitLab and Gitea.
+	BaseURL string `mapstructure:"baseUrl" desc:"API root of the forge"`
+	// Repository is owner/name, for GitLab the full project path.
+	Repository string `mapstructure:"repository" desc:"Repository as owner/name"`
+	// TokenEnv names the environment variable holding the API token.
+	TokenEnv string   `mapstructure:"tokenEnv" desc:"Environment variable holding the API token"`
+	Assets   []string `mapstructure:"assets" desc:"Files attached to releases"`
+}
+
+// Release is a release to create for an existing tag.
+type Release struct {
+	Tag        string
+	Name       string
+	Notes      string
+	PreRelease bool
+	// Assets are paths of files attached to the release.
+	Assets []string
+}
+
+// Result describes the published release.
+type Result struct {
+	URL            string
+	Created        bool
+	UploadedAssets []string
+	SkippedAssets  []string
+}
+
+// Publisher creates or updates a release on a forge.
+type Publisher interface {
+	Publish(ctx context.Context, release Release) (Result, error)
+}
+
+// New creates the publisher for the configured provider.
+func New(cfg Config, token string, client *http.Client) (Publisher, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
+
+	owner, name, found := strings.Cut(cfg.Repository, "/")
+	if !found || owner == "" || name == "" {
+		return nil, InvalidConfigError("repository must be owner/name")
+	}
+
+	api := apiClient{client: client, token: token}
+
+	switch cfg.Provider {
+	case ProviderGitHub:
+		base := cfg.BaseURL
+		if base == "" {
+			base = DefaultGitHubURL
+		}
+		api.baseURL = strings.TrimSuffix(base, "/")
+		api.authorize = func(req *http.Request, token string) {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return &gitHub{api: api, repository: cfg.Repository}, nil
+	case ProviderGitea:
+		if cfg.BaseURL == "" {
+			return nil, InvalidConfigError("baseUrl is required for gitea")
+		}
+		api.baseURL = strings.TrimSuffix(cfg.BaseURL, "/") + "/api/v1"
+		api.authorize = func(req *http.Request, token string) {
+			req.Header.Set("Authorization", "token "+token)
+		}
+		return &gitea{api: api, repository: cfg.Repository}, nil
+	case ProviderGitLab:
+		if cfg.BaseURL == "" {
+			return nil, InvalidConfigError("baseUrl is required for gitlab")
+		}
+		api.baseURL = strings.TrimSuffix(cfg.BaseURL, "/") + "/api/v4"
+		api.authorize = func(req *http.Request, token string) {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		}
+		return &gitLab{api: api, webURL: strings.TrimSuffix(cfg.BaseURL, "/"), project: cfg.Repository}, nil
+	default:
+		return nil, InvalidConfigError(fmt.Sprintf("unknown provider %q", cfg.Provider))
+	}
+}
+
+// apiClient performs authenticated JSON requests against a forge API.
+type apiClient struct {
+	client  *http.Client
+	baseURL string
+	token   string
+	// authorize sets the authentication header of a request.
+	authorize func(req *http.Request, token string)
+}
+
+// do sends a request and decodes the JSON response into out. Non-2xx status
+// codes are APIErrors.
+func (a apiClient) do(ctx context.Context, method, url string, body io.Reader, contentType string, out any) error {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		url = a.baseURL + url
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if a.token != "" && a.authorize != nil {
+		a.authorize(req, a.token)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return RequestError{method, url, err}
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return RequestError{method, url, err}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return APIError{method, url, resp.StatusCode, strings.TrimSpace(string(data))}
+	}
+
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return RequestError{method, url, err}
+		}
+	}
+
+	return nil
+}
+
+func (a apiClient) doJSON(ctx context.Context, method, url string, in any, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return a.do(ctx, method, url, bytes.NewReader(data), "application/json", out)
+}
+
+// find gets a resource that may not exist. It returns false without error
+// if the forge answers 404.
+func (a apiClient) find(ctx context.Context, url string, out any) (bool, error) {
+	err := a.do(ctx, http.MethodGet, url, nil, "", out)
+	var apiErr APIError
+	if errors.As(err, &apiErr) && apiErr.status == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// assetName returns the file name an asset is published under.
+func assetName(path string) string {
+	return filepath.Base(path)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
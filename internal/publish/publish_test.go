@@ -0,0 +1,226 @@
+package publish
+
+import (
+	"context"
+	"gotver/internal/exceptions"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// route is the response of the mock forge to a request.
+type route struct {
+	status int
+	body   string
+}
+
+// forge describes the requests a provider sends. Release bodies may contain
+// {{url}}, it is replaced with the URL of the mock forge.
+type forge struct {
+	provider string
+	lookup   string
+	create   string
+	update   string
+	// uploads are the requests uploading a single asset.
+	uploads []string
+	// created is a new release, existing a release with the asset a.txt.
+	created  string
+	existing string
+}
+
+var forges = []forge{
+	{
+		provider: ProviderGitHub,
+		lookup:   "GET /repos/owner/name/releases/tags/v1.0.0",
+		create:   "POST /repos/owner/name/releases",
+		update:   "PATCH /repos/owner/name/releases/1",
+		uploads:  []string{"POST /uploads/assets"},
+		created:  `{"id": 1, "html_url": "https://forge/r", "upload_url": "{{url}}/uploads/assets{?name,label}"}`,
+		existing: `{"id": 1, "html_url": "https://forge/r", "upload_url": "{{url}}/uploads/assets{?name,label}", "assets": [{"name": "a.txt"}]}`,
+	},
+	{
+		provider: ProviderGitea,
+		lookup:   "GET /api/v1/repos/owner/name/releases/tags/v1.0.0",
+		create:   "POST /api/v1/repos/owner/name/releases",
+		update:   "PATCH /api/v1/repos/owner/name/releases/1",
+		uploads:  []string{"POST /api/v1/repos/owner/name/releases/1/assets"},
+		created:  `{"id": 1, "html_url": "https://forge/r"}`,
+		existing: `{"id": 1, "html_url": "https://forge/r", "assets": [{"name": "a.txt"}]}`,
+	},
+	{
+		provider: ProviderGitLab,
+		lookup:   "GET /api/v4/projects/owner%2Fname/releases/v1.0.0",
+		create:   "POST /api/v4/projects/owner%2Fname/releases",
+		update:   "PUT /api/v4/projects/owner%2Fname/releases/v1.0.0",
+		uploads: []string{
+			"POST /api/v4/projects/owner%2Fname/uploads",
+			"POST /api/v4/projects/owner%2Fname/releases/v1.0.0/assets/links",
+		},
+		created:  `{"tag_name": "v1.0.0", "_links": {"self": "https://forge/r"}}`,
+		existing: `{"tag_name": "v1.0.0", "_links": {"self": "https://forge/r"}, "assets": {"links": [{"name": "a.txt"}]}}`,
+	},
+}
+
+// publish publishes the release v1.0.0 with the assets a.txt and b.txt to a
+// mock forge answering with routes. It returns the requests sent.
+func publish(t *testing.T, f forge, routes map[string]route) (Result, []string, error) {
+	t.Helper()
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.EscapedPath()
+		requests = append(requests, request)
+		if r.Header.Get("Authorization") == "" && r.Header.Get("PRIVATE-TOKEN") == "" {
+			t.Errorf("request %s is not authenticated", request)
+		}
+
+		if slices.Contains(f.uploads, request) && r.ContentLength <= 0 {
+			t.Errorf("upload %s has no Content-Length", request)
+		}
+
+		rt, ok := routes[request]
+		if !ok {
+			t.Errorf("unexpected request %s", request)
+			w.WriteHeader(http.StatusTeapot)
+			return
+		}
+		w.WriteHeader(rt.status)
+		w.Write([]byte(strings.ReplaceAll(rt.body, "{{url}}", "http://"+r.Host)))
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	var assets []string
+	for _, name := range []string{"a.txt", "b.txt"} {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(name), 0o644); err != nil {
+			t.Fatalf("write asset: %v", err)
+		}
+		assets = append(assets, file)
+	}
+
+	p, err := New(Config{Provider: f.provider, BaseURL: server.URL, Repository: "owner/name"}, "secret", server.Client())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	result, err := p.Publish(context.Background(), Release{Tag: "v1.0.0", Name: "1.0.0", Notes: "notes", Assets: assets})
+	return result, requests, err
+}
+
+// uploadRoutes answers the upload requests of a forge with status.
+func uploadRoutes(f forge, routes map[string]route, status int) map[string]route {
+	for _, upload := range f.uploads {
+		routes[upload] = route{status, `{"full_path": "/uploads/0123/file"}`}
+	}
+	return routes
+}
+
+func TestPublishCreate(t *testing.T) {
+	for _, f := range forges {
+		t.Run(f.provider, func(t *testing.T) {
+			routes := uploadRoutes(f, map[string]route{
+				f.lookup: {http.StatusNotFound, `{"message": "Not Found"}`},
+				f.create: {http.StatusCreated, f.created},
+			}, http.StatusCreated)
+
+			result, requests, err := publish(t, f, routes)
+			if err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+
+			want := Result{URL: "https://forge/r", Created: true, UploadedAssets: []string{"a.txt", "b.txt"}}
+			if !reflect.DeepEqual(result, want) {
+				t.Errorf("Publish() = %+v, want %+v", result, want)
+			}
+			if len(requests) != 2+2*len(f.uploads) {
+				t.Errorf("requests = %q, want a lookup, a create and the uploads", requests)
+			}
+		})
+	}
+}
+
+func TestPublishUpdate(t *testing.T) {
+	for _, f := range forges {
+		t.Run(f.provider, func(t *testing.T) {
+			routes := uploadRoutes(f, map[string]route{
+				f.lookup: {http.StatusOK, f.existing},
+				f.update: {http.StatusOK, f.created},
+			}, http.StatusCreated)
+
+			result, requests, err := publish(t, f, routes)
+			if err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+
+			want := Result{URL: "https://forge/r", UploadedAssets: []string{"b.txt"}, SkippedAssets: []string{"a.txt"}}
+			if !reflect.DeepEqual(result, want) {
+				t.Errorf("Publish() = %+v, want %+v", result, want)
+			}
+			if len(requests) < 2 || requests[1] != f.update {
+				t.Errorf("requests = %q, want the release updated with %s", requests, f.update)
+			}
+		})
+	}
+}
+
+func TestPublishErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes func(f forge) map[string]route
+		want   exceptions.Code
+	}{
+		{
+			name: "lookup unauthorized",
+			routes: func(f forge) map[string]route {
+				return map[string]route{f.lookup: {http.StatusUnauthorized, `{"message": "Bad credentials"}`}}
+			},
+			want: exceptions.PublishAPI,
+		},
+		{
+			name: "create not found",
+			routes: func(f forge) map[string]route {
+				return map[string]route{
+					f.lookup: {http.StatusNotFound, ""},
+					f.create: {http.StatusNotFound, `{"message": "Not Found"}`},
+				}
+			},
+			want: exceptions.PublishAPI,
+		},
+		{
+			name: "update forbidden",
+			routes: func(f forge) map[string]route {
+				return map[string]route{
+					f.lookup: {http.StatusOK, f.existing},
+					f.update: {http.StatusForbidden, ""},
+				}
+			},
+			want: exceptions.PublishAPI,
+		},
+		{
+			name: "upload not found",
+			routes: func(f forge) map[string]route {
+				return uploadRoutes(f, map[string]route{
+					f.lookup: {http.StatusNotFound, ""},
+					f.create: {http.StatusCreated, f.created},
+				}, http.StatusNotFound)
+			},
+			want: exceptions.PublishAsset,
+		},
+	}
+
+	for _, f := range forges {
+		for _, tt := range tests {
+			t.Run(f.provider+"/"+tt.name, func(t *testing.T) {
+				_, _, err := publish(t, f, tt.routes(f))
+				if exceptions.CodeOf(err) != tt.want {
+					t.Errorf("Publish() error = %v, want code %d", err, tt.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultCalVerFormat = "YYYY.0M.MICRO"
+)
+
+// clock is the clock of the CalVer schemes created by NewScheme.
+var clock = time.Now
+
+// SetClock sets the clock the dates of CalVer schemes created by NewScheme
+// are taken from, nil restores time.Now.
+func SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	clock = now
+}
+
+// calVerTokens are the supported format tokens, see https://calver.org.
+// N is accepted as a short form of MICRO.
+var calVerTokens = map[string]bool{
+	"YYYY":  true,
+	"YY":    true,
+	"0Y":    true,
+	"MM":    true,
+	"0M":    true,
+	"WW":    true,
+	"0W":    true,
+	"DD":    true,
+	"0D":    true,
+	"MICRO": true,
+	"N":     true,
+}
+
+// CalVer is a calendar versioning scheme like YYYY.MM.MICRO or YY.0W.N. The
+// date segments are taken from the clock on every bump, the micro counter
+// is incremented within the same period and reset otherwise.
+type CalVer struct {
+	tokens []string
+	now    func() time.Time
+}
+
+// NewCalVer creates a CalVer scheme for the format. A nil clock uses
+// time.Now.
+func NewCalVer(format string, now func() time.Time) (*CalVer, error) {
+	if format == "" {
+		format = DefaultCalVerFormat
+	}
+	if now == nil {
+		now = time.Now
+	}
+
+	tokens := strings.Split(format, ".")
+	micro := 0
+	for _, token := range tokens {
+		if !calVerTokens[token] {
+			return nil, CalVerFormatError(format)
+		}
+		if isMicro(token) {
+			micro++
+		}
+	}
+	if micro > 1 || (micro == 1 && !isMicro(tokens[len(tokens)-1])) {
+		return nil, CalVerFormatError(format)
+	}
+
+	return &CalVer{tokens: tokens, now: now}, nil
+}
+
+func (c *CalVer) Name() string {
+	return SchemeCalVer
+}
+
+func (c *CalVer) Segments() []string {
+	segments := make([]string, len(c.tokens))
+	copy(segments, c.tokens)
+	return segments
+}
+
+func (c *CalVer) Parse(core string) ([]int, error) {
+	parts := strings.Split(core, ".")
+	if len(parts) != len(c.tokens) {
+		return nil, fmt.Errorf("expected %d segments in %q", len(c.tokens), core)
+	}
+
+	segments := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || c.formatToken(c.tokens[i], n) != part {
+			return nil, fmt.Errorf("invalid segment %q in %q", part, core)
+		}
+		segments[i] = n
+	}
+	return segments, nil
+}
+
+func (c *CalVer) Format(segments []int) string {
+	parts := make([]string, len(segments))
+	for i, n := range segments {
+		parts[i] = c.formatToken(c.tokens[i], n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// Bump ignores the segment, the next version is defined by the date. If the
+// date segments did not change, the micro counter is incremented.
+func (c *CalVer) Bump(segments []int, _ string) ([]int, error) {
+	next := c.dateSegments()
+
+	samePeriod := len(segments) == len(next)
+	for i, token := range c.tokens {
+		if !isMicro(token) && samePeriod && segments[i] != next[i] {
+			samePeriod = false
+		}
+	}
+
+	last := len(c.tokens) - 1
+	if isMicro(c.tokens[last]) {
+		if samePeriod {
+			next[last] = segments[last] + 1
+		}
+		return next, nil
+	}
+
+	if samePeriod {
+		return nil, CalVerPeriodError(c.Format(segments))
+	}
+	return next, nil
+}
+
+// dateSegments returns the segments for the current date with a micro
+// counter of zero.
+func (c *CalVer) dateSegments() []int {
+	now := c.now()
+	year := now.Year()
+	isoYear, week := now.ISOWeek()
+
+	// Week based versions use the ISO year so the week never goes back.
+	for _, token := range c.tokens {
+		if token == "WW" || token == "0W" {
+			year = isoYear
+		}
+	}
+
+	segments := make([]int, len(c.tokens))
+	for i, token := range c.tokens {
+		switch token {
+		case "YYYY":
+			segments[i] = year
+		case "YY", "0Y":
+			segments[i] = year - 2000
+		case "MM", "0M":
+			segments[i] = int(now.Month())
+		case "WW", "0W":
+			segments[i] = week
+		case "DD", "0D":
+			segments[i] = now.Day()
+		}
+	}
+	return segments
+}
+
+func (c *CalVer) formatToken(token string, n int) string {
+	switch token {
+	case "0Y", "0M", "0W", "0D":
+		return fmt.Sprintf("%02d", n)
+	default:
+		return strconv.Itoa(n)
+	}
+}
+
+func isMicro(token string) bool {
+	return token == "MICRO" || token == "N"
+}
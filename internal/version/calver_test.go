@@ -0,0 +1,98 @@
+package version
+
+import (
+	"gotver/internal/exceptions"
+	"testing"
+	"time"
+)
+
+// fixedClock returns a clock standing still at the date.
+func fixedClock(year int, month time.Month, day int) func() time.Time {
+	return func() time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+	}
+}
+
+func TestCalVerBump(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		today   func() time.Time
+		version string
+		want    string
+	}{
+		{"same month", "YYYY.0M.MICRO", fixedClock(2024, time.May, 20), "2024.05.3", "2024.05.4"},
+		{"next month resets micro", "YYYY.0M.MICRO", fixedClock(2024, time.May, 20), "2024.04.3", "2024.05.0"},
+		{"next year", "YYYY.0M.MICRO", fixedClock(2024, time.January, 2), "2023.12.7", "2024.01.0"},
+		{"short year", "YY.MM.N", fixedClock(2024, time.May, 1), "24.4.1", "24.5.0"},
+		{"ISO week of the next year", "YY.0W.N", fixedClock(2024, time.December, 30), "24.52.2", "25.01.0"},
+		{"next day", "YYYY.0M.0D", fixedClock(2024, time.May, 20), "2024.05.19", "2024.05.20"},
+		{"year and micro", "YYYY.MICRO", fixedClock(2024, time.March, 1), "2024.9", "2024.10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme, err := NewCalVer(tt.format, tt.today)
+			if err != nil {
+				t.Fatalf("NewCalVer(%q) error = %v", tt.format, err)
+			}
+
+			v := newVersion(t, scheme, tt.version)
+			if err := v.BumpPatch(); err != nil {
+				t.Fatalf("BumpPatch() error = %v", err)
+			}
+			if got := v.ToString(); got != tt.want {
+				t.Errorf("BumpPatch() of %s = %s, want %s", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalVerBumpSamePeriod(t *testing.T) {
+	scheme, err := NewCalVer("YYYY.0M.0D", fixedClock(2024, time.May, 20))
+	if err != nil {
+		t.Fatalf("NewCalVer() error = %v", err)
+	}
+
+	v := newVersion(t, scheme, "2024.05.20")
+	if err := v.BumpPatch(); exceptions.CodeOf(err) != exceptions.CalVerPeriod {
+		t.Errorf("BumpPatch() error = %v, want a CalVerPeriodError", err)
+	}
+}
+
+func TestCalVerFormat(t *testing.T) {
+	scheme, err := NewCalVer("", nil)
+	if err != nil || scheme.Format([]int{2024, 5, 0}) != "2024.05.0" {
+		t.Errorf("NewCalVer() with the default format = %v, %v", scheme, err)
+	}
+
+	for _, format := range []string{"YYYY.Q", "YYYY.MICRO.MM", "YYYY.MICRO.N"} {
+		if _, err := NewCalVer(format, nil); exceptions.CodeOf(err) != exceptions.CalVerFormat {
+			t.Errorf("NewCalVer(%q) error = %v, want a CalVerFormatError", format, err)
+		}
+	}
+
+	for _, core := range []string{"2024.5.0", "2024.05", "2024.13a.0"} {
+		if _, err := scheme.Parse(core); err == nil {
+			t.Errorf("Parse(%q) error = nil, want an error", core)
+		}
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	SetClock(fixedClock(2025, time.February, 1))
+	t.Cleanup(func() { SetClock(nil) })
+
+	scheme, err := NewScheme(SchemeCalVer, "YYYY.0M.MICRO", nil)
+	if err != nil {
+		t.Fatalf("NewScheme() error = %v", err)
+	}
+
+	v := newVersion(t, scheme, "2025.01.4")
+	if err := v.BumpMajor(); err != nil {
+		t.Fatalf("BumpMajor() error = %v", err)
+	}
+	if got := v.ToString(); got != "2025.02.0" {
+		t.Errorf("BumpMajor() = %s, want 2025.02.0", got)
+	}
+}
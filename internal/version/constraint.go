@@ -0,0 +1,369 @@
+package version
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Constraint is a parsed version constraint like `>=2.3 <3`, `^1.4`,
+// `1.2 - 1.5` or `~1.2 || ^2`. Alternatives are separated by `||`, the
+// comparisons of an alternative by whitespace or commas and must all hold.
+type Constraint struct {
+	text         string
+	alternatives [][]comparison
+}
+
+type comparison struct {
+	op      string
+	version looseVersion
+}
+
+// looseVersion is a version with any number of numeric segments, missing
+// segments compare as zero.
+type looseVersion struct {
+	segments   []int
+	preRelease string
+}
+
+const (
+	opEqual        = "="
+	opNotEqual     = "!="
+	opGreater      = ">"
+	opGreaterEqual = ">="
+	opLess         = "<"
+	opLessEqual    = "<="
+)
+
+// operators are ordered so longer operators are matched first.
+var operators = []string{opGreaterEqual, opLessEqual, opNotEqual, opGreater, opLess, opEqual, "~", "^"}
+
+// ParseConstraint parses a version constraint.
+func ParseConstraint(text string) (*Constraint, error) {
+	c := &Constraint{text: text}
+
+	for _, alternative := range strings.Split(text, "||") {
+		comparisons, err := parseAlternative(strings.TrimSpace(alternative))
+		if err != nil {
+			return nil, ConstraintError(text)
+		}
+		c.alternatives = append(c.alternatives, comparisons)
+	}
+
+	return c, nil
+}
+
+func (c *Constraint) String() string {
+	return c.text
+}
+
+// Check reports whether the version satisfies the constraint.
+func (c *Constraint) Check(version string) (bool, error) {
+	lv, wildcard, err := parseLoose(version)
+	if err != nil || lv == nil || wildcard {
+		return false, InputValueError(version)
+	}
+
+	for _, comparisons := range c.alternatives {
+		satisfied := true
+		for _, cmp := range comparisons {
+			if !cmp.check(*lv) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Satisfies reports whether the current version satisfies the constraint.
+func Satisfies(constraint string) (bool, error) {
+	return v.Satisfies(constraint)
+}
+
+func (v *Version) Satisfies(constraint string) (bool, error) {
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	return c.Check(v.ToString())
+}
+
+// Compare compares two versions and returns -1, 0 or 1. Versions may have
+// any number of segments, pre-releases have a lower precedence than the
+// release.
+func Compare(a, b string) (int, error) {
+	va, wildcard, err := parseLoose(a)
+	if err != nil || va == nil || wildcard {
+		return 0, InputValueError(a)
+	}
+
+	vb, wildcard, err := parseLoose(b)
+	if err != nil || vb == nil || wildcard {
+		return 0, InputValueError(b)
+	}
+
+	return va.compare(*vb), nil
+}
+
+func parseAlternative(text string) ([]comparison, error) {
+	if text == "" {
+		return nil, ConstraintError(text)
+	}
+
+	// Hyphen ranges: `1.2 - 1.5`
+	if lower, upper, found := strings.Cut(text, " - "); found {
+		return parseHyphenRange(strings.TrimSpace(lower), strings.TrimSpace(upper))
+	}
+
+	var comparisons []comparison
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ','
+	})
+
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+
+		// Allow a space between operator and version: `>= 1.2`
+		if isOperator(field) && i+1 < len(fields) {
+			i++
+			field += fields[i]
+		}
+
+		parsed, err := parseComparison(field)
+		if err != nil {
+			return nil, err
+		}
+		comparisons = append(comparisons, parsed...)
+	}
+
+	return comparisons, nil
+}
+
+func parseHyphenRange(lower, upper string) ([]comparison, error) {
+	lv, _, err := parseLoose(lower)
+	if err != nil {
+		return nil, err
+	}
+
+	uv, wildcard, err := parseLoose(upper)
+	if err != nil {
+		return nil, err
+	}
+
+	var comparisons []comparison
+	if lv != nil {
+		comparisons = append(comparisons, comparison{opGreaterEqual, *lv})
+	}
+	if uv != nil {
+		if wildcard {
+			comparisons = append(comparisons, comparison{opLess, uv.increment(len(uv.segments) - 1)})
+		} else {
+			comparisons = append(comparisons, comparison{opLessEqual, *uv})
+		}
+	}
+	return comparisons, nil
+}
+
+func parseComparison(text string) ([]comparison, error) {
+	op := ""
+	for _, candidate := range operators {
+		if strings.HasPrefix(text, candidate) {
+			op = candidate
+			break
+		}
+	}
+
+	lv, wildcard, err := parseLoose(strings.TrimPrefix(text, op))
+	if err != nil {
+		return nil, err
+	}
+
+	// `*`, `x` and operators on them match every version.
+	if lv == nil {
+		return nil, nil
+	}
+
+	last := len(lv.segments) - 1
+	switch op {
+	case "~":
+		index := 0
+		if len(lv.segments) > 1 {
+			index = 1
+		}
+		return []comparison{{opGreaterEqual, *lv}, {opLess, lv.increment(index)}}, nil
+	case "^":
+		index := last
+		for i, n := range lv.segments {
+			if n != 0 {
+				index = i
+				break
+			}
+		}
+		return []comparison{{opGreaterEqual, *lv}, {opLess, lv.increment(index)}}, nil
+	case "", opEqual:
+		if wildcard {
+			return []comparison{{opGreaterEqual, *lv}, {opLess, lv.increment(last)}}, nil
+		}
+		return []comparison{{opEqual, *lv}}, nil
+	case opLessEqual:
+		if wildcard {
+			return []comparison{{opLess, lv.increment(last)}}, nil
+		}
+	case opGreater:
+		if wildcard {
+			return []comparison{{opGreaterEqual, lv.increment(last)}}, nil
+		}
+	}
+
+	return []comparison{{op, *lv}}, nil
+}
+
+// parseLoose parses a version with optional leading `v`. A trailing `x`,
+// `X` or `*` segment is a wildcard and reported as such, a version
+// consisting only of a wildcard returns nil.
+func parseLoose(text string) (*looseVersion, bool, error) {
+	text = strings.TrimPrefix(strings.TrimSpace(text), "v")
+	if text == "" {
+		return nil, false, ConstraintError(text)
+	}
+
+	core, preRelease, _ := strings.Cut(text, "-")
+	parts := strings.Split(core, ".")
+
+	wildcard := false
+	segments := make([]int, 0, len(parts))
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			if i != len(parts)-1 || preRelease != "" {
+				return nil, false, ConstraintError(text)
+			}
+			wildcard = true
+			break
+		}
+
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false, ConstraintError(text)
+		}
+		segments = append(segments, n)
+	}
+
+	if len(segments) == 0 {
+		return nil, wildcard, nil
+	}
+
+	return &looseVersion{segments: segments, preRelease: preRelease}, wildcard, nil
+}
+
+func isOperator(text string) bool {
+	for _, op := range operators {
+		if text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparison) check(version looseVersion) bool {
+	result := version.compare(c.version)
+	switch c.op {
+	case opEqual:
+		return result == 0
+	case opNotEqual:
+		return result != 0
+	case opGreater:
+		return result > 0
+	case opGreaterEqual:
+		return result >= 0
+	case opLess:
+		return result < 0
+	case opLessEqual:
+		return result <= 0
+	}
+	return false
+}
+
+// increment returns the version with the segment at index incremented and
+// all less significant segments dropped.
+func (l looseVersion) increment(index int) looseVersion {
+	segments := make([]int, index+1)
+	copy(segments, l.segments[:index+1])
+	segments[index]++
+	return looseVersion{segments: segments}
+}
+
+func (l looseVersion) compare(other looseVersion) int {
+	count := len(l.segments)
+	if len(other.segments) > count {
+		count = len(other.segments)
+	}
+
+	for i := 0; i < count; i++ {
+		a, b := segmentOrZero(l.segments, i), segmentOrZero(other.segments, i)
+		if a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return comparePreRelease(l.preRelease, other.preRelease)
+}
+
+func segmentOrZero(segments []int, index int) int {
+	if index < len(segments) {
+		return segments[index]
+	}
+	return 0
+}
+
+// comparePreRelease compares pre-release identifiers following the SemVer
+// precedence rules.
+func comparePreRelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an < bn {
+				return -1
+			}
+			return 1
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		case as[i] < bs[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,123 @@
+package version
+
+import (
+	"gotver/internal/exceptions"
+	"testing"
+)
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		// Operators
+		{"1.2.3", "1.2.3", true},
+		{"1.2.3", "1.2.4", false},
+		{"=1.2", "1.2.0", true},
+		{"v1.2.3", "v1.2.3", true},
+		{"!=1.2.3", "1.2.3", false},
+		{"!=1.2.3", "1.2.4", true},
+		{">1.2", "1.2.1", true},
+		{">1.2", "1.2.0", false},
+		{">= 1.2", "1.2.0", true},
+		{"<2", "1.9.9", true},
+		{"<2", "2.0.0", false},
+		{"<=1.2", "1.2.0", true},
+		{"<=1.2", "1.2.1", false},
+
+		// Ranges
+		{">=2.3 <3", "2.9.0", true},
+		{">=2.3 <3", "3.0.0", false},
+		{">=2.3, <3", "2.2.9", false},
+		{"~1.2", "1.2.9", true},
+		{"~1.2", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{"~1", "2.0.0", false},
+		{"^1.4", "1.9.0", true},
+		{"^1.4", "2.0.0", false},
+		{"^1.4", "1.3.9", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"1.2 - 1.5", "1.5.0", true},
+		{"1.2 - 1.5", "1.5.1", false},
+		{"1.2 - 1.x", "1.9.9", true},
+		{"1.2 - 1.x", "2.0.0", false},
+		{"1.2.x", "1.2.7", true},
+		{"1.2.x", "1.3.0", false},
+		{"*", "0.0.1", true},
+		{"<=1.2.x", "1.2.9", true},
+		{"<=1.2.x", "1.3.0", false},
+		{">1.2.x", "1.3.0", true},
+		{">1.2.x", "1.2.9", false},
+		{"~1.2 || ^2", "2.5.0", true},
+		{"~1.2 || ^2", "1.5.0", false},
+
+		// Pre-releases have a lower precedence than their release
+		{">=1.0.0", "1.0.0-beta.1", false},
+		{"<1.0.0", "1.0.0-rc.1", true},
+		{"^1.4", "1.5.0-beta.1", true},
+		{">1.0.0-beta.2", "1.0.0-beta.10", true},
+		{">1.0.0-alpha", "1.0.0-beta", true},
+		{">1.0.0-alpha", "1.0.0-alpha.1", true},
+		{"=1.0.0-beta", "1.0.0-beta", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+"/"+tt.version, func(t *testing.T) {
+			c, err := ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q) error = %v", tt.constraint, err)
+			}
+			got, err := c.Check(tt.version)
+			if err != nil {
+				t.Fatalf("Check(%q) error = %v", tt.version, err)
+			}
+			if got != tt.want {
+				t.Errorf("%q.Check(%q) = %t, want %t", tt.constraint, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConstraintInvalid(t *testing.T) {
+	for _, constraint := range []string{"", ">=", "abc", "1.x.2", "1.x-beta", "1.2 ||", ">=1.2 <", "-1"} {
+		t.Run(constraint, func(t *testing.T) {
+			if _, err := ParseConstraint(constraint); exceptions.CodeOf(err) != exceptions.Constraint {
+				t.Errorf("ParseConstraint(%q) error = %v, want a ConstraintError", constraint, err)
+			}
+		})
+	}
+}
+
+func TestConstraintCheckInvalid(t *testing.T) {
+	c, err := ParseConstraint(">=1")
+	if err != nil {
+		t.Fatalf("ParseConstraint() error = %v", err)
+	}
+	for _, version := range []string{"", "abc", "*", "1.x"} {
+		if _, err := c.Check(version); exceptions.CodeOf(err) != exceptions.InputValue {
+			t.Errorf("Check(%q) error = %v, want an InputValueError", version, err)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2", "1.2.0", 0},
+		{"2", "1.9", 1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.2.3.4", "1.2.3", 1},
+	}
+
+	for _, tt := range tests {
+		if got, err := Compare(tt.a, tt.b); err != nil || got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, %v, want %d", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+}
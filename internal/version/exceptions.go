@@ -1,51 +1,98 @@
 package version
 
-import "fmt"
-
-const (
-	unhandledErrorCode = iota + 2000
-	fileAlreadyExistsErrorCode
-	fileNotFoundErrorCode
-	fileFormatErrorCode
-	projectDirectoryNotFoundErrorCode
-	inputValueErrorCode
-	writeOperationFailedErrorCode
+import (
+	"fmt"
+	"gotver/internal/exceptions"
 )
 
 type UnhandledError string
 
 func (p UnhandledError) Error() string {
-	return fmt.Sprintf("error code: %d - unhandled exception: %q", unhandledErrorCode, string(p))
+	return fmt.Sprintf("error code: %d - unhandled exception: %q", exceptions.Unhandled, string(p))
+}
+
+func (p UnhandledError) Code() exceptions.Code {
+	return exceptions.Unhandled
+}
+
+func (p UnhandledError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.Unhandled, nil)
 }
 
 type FileAlreadyExistsError string
 
 func (p FileAlreadyExistsError) Error() string {
-	return fmt.Sprintf("error code: %d - Version File %q Already Exists", fileAlreadyExistsErrorCode, string(p))
+	return fmt.Sprintf("error code: %d - version file %q already exists", exceptions.FileAlreadyExists, string(p))
 }
 
-type FileNotFoundError string
+func (p FileAlreadyExistsError) Code() exceptions.Code {
+	return exceptions.FileAlreadyExists
+}
+
+func (p FileAlreadyExistsError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.FileAlreadyExists, nil)
+}
+
+type FileNotFoundError struct {
+	file  string
+	error error
+}
 
 func (p FileNotFoundError) Error() string {
-	return fmt.Sprintf("error code: %d - Version File %q Already Exists", fileNotFoundErrorCode, string(p))
+	return fmt.Sprintf("error code: %d - version file %q not found: %v", exceptions.FileNotFound, p.file, p.error)
 }
 
-type FileFormatError string
+func (p FileNotFoundError) Code() exceptions.Code {
+	return exceptions.FileNotFound
+}
+
+func (p FileNotFoundError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.FileNotFound, p.error)
+}
+
+type FileFormatError struct {
+	file  string
+	error error
+}
 
 func (p FileFormatError) Error() string {
-	return fmt.Sprintf("error code: %d - Version File %q Already Exists", fileFormatErrorCode, string(p))
+	return fmt.Sprintf("error code: %d - version file %q has an invalid format: %v", exceptions.FileFormat, p.file, p.error)
+}
+
+func (p FileFormatError) Code() exceptions.Code {
+	return exceptions.FileFormat
+}
+
+func (p FileFormatError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.FileFormat, p.error)
 }
 
 type ProjectDirectoryNotFoundError string
 
 func (p ProjectDirectoryNotFoundError) Error() string {
-	return fmt.Sprintf("error code: %d - Project Directory not Found %q", projectDirectoryNotFoundErrorCode, string(p))
+	return fmt.Sprintf("error code: %d - project directory not found from %q", exceptions.ProjectDirectoryNotFound, string(p))
+}
+
+func (p ProjectDirectoryNotFoundError) Code() exceptions.Code {
+	return exceptions.ProjectDirectoryNotFound
+}
+
+func (p ProjectDirectoryNotFoundError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.ProjectDirectoryNotFound, nil)
 }
 
 type InputValueError string
 
 func (p InputValueError) Error() string {
-	return fmt.Sprintf("error code: %d - Project Directory not Found %q", inputValueErrorCode, string(p))
+	return fmt.Sprintf("error code: %d - invalid version %q", exceptions.InputValue, string(p))
+}
+
+func (p InputValueError) Code() exceptions.Code {
+	return exceptions.InputValue
+}
+
+func (p InputValueError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.InputValue, nil)
 }
 
 type WriteOperationFailedError struct {
@@ -54,5 +101,142 @@ type WriteOperationFailedError struct {
 }
 
 func (p WriteOperationFailedError) Error() string {
-	return fmt.Sprintf("error code: %d - Project Directory not Found %q %q", writeOperationFailedErrorCode, p.file, p.error)
+	return fmt.Sprintf("error code: %d - file %q could not be written: %v", exceptions.WriteOperationFailed, p.file, p.error)
+}
+
+func (p WriteOperationFailedError) Code() exceptions.Code {
+	return exceptions.WriteOperationFailed
+}
+
+func (p WriteOperationFailedError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.WriteOperationFailed, p.error)
+}
+
+type BumpNotAllowedError string
+
+func (p BumpNotAllowedError) Error() string {
+	return fmt.Sprintf("error code: %d - %s bump not allowed", exceptions.BumpNotAllowed, string(p))
+}
+
+func (p BumpNotAllowedError) Code() exceptions.Code {
+	return exceptions.BumpNotAllowed
+}
+
+func (p BumpNotAllowedError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.BumpNotAllowed, nil)
+}
+
+type VersionLineError string
+
+func (p VersionLineError) Error() string {
+	return fmt.Sprintf("error code: %d - invalid version line %q", exceptions.VersionLine, string(p))
+}
+
+func (p VersionLineError) Code() exceptions.Code {
+	return exceptions.VersionLine
+}
+
+func (p VersionLineError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.VersionLine, nil)
+}
+
+type OutsideVersionLineError struct {
+	version string
+	line    string
+}
+
+func (p OutsideVersionLineError) Error() string {
+	return fmt.Sprintf("error code: %d - %s leaves maintenance line %s", exceptions.OutsideVersionLine, p.version, p.line)
+}
+
+func (p OutsideVersionLineError) Code() exceptions.Code {
+	return exceptions.OutsideVersionLine
+}
+
+func (p OutsideVersionLineError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.OutsideVersionLine, nil)
+}
+
+type UnknownSegmentError string
+
+func (p UnknownSegmentError) Error() string {
+	return fmt.Sprintf("error code: %d - unknown version segment %q", exceptions.UnknownSegment, string(p))
+}
+
+func (p UnknownSegmentError) Code() exceptions.Code {
+	return exceptions.UnknownSegment
+}
+
+func (p UnknownSegmentError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.UnknownSegment, nil)
+}
+
+type UnknownSchemeError string
+
+func (p UnknownSchemeError) Error() string {
+	return fmt.Sprintf("error code: %d - unknown versioning scheme %q", exceptions.UnknownScheme, string(p))
+}
+
+func (p UnknownSchemeError) Code() exceptions.Code {
+	return exceptions.UnknownScheme
+}
+
+func (p UnknownSchemeError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.UnknownScheme, nil)
+}
+
+type CalVerFormatError string
+
+func (p CalVerFormatError) Error() string {
+	return fmt.Sprintf("error code: %d - invalid calendar version format %q", exceptions.CalVerFormat, string(p))
+}
+
+func (p CalVerFormatError) Code() exceptions.Code {
+	return exceptions.CalVerFormat
+}
+
+func (p CalVerFormatError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.CalVerFormat, nil)
+}
+
+type CalVerPeriodError string
+
+func (p CalVerPeriodError) Error() string {
+	return fmt.Sprintf("error code: %d - version %q is already current and the format has no MICRO counter", exceptions.CalVerPeriod, string(p))
+}
+
+func (p CalVerPeriodError) Code() exceptions.Code {
+	return exceptions.CalVerPeriod
+}
+
+func (p CalVerPeriodError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.CalVerPeriod, nil)
+}
+
+type SegmentDefinitionError string
+
+func (p SegmentDefinitionError) Error() string {
+	return fmt.Sprintf("error code: %d - scheme %q needs at least one segment and unique segment names", exceptions.SegmentDefinition, string(p))
+}
+
+func (p SegmentDefinitionError) Code() exceptions.Code {
+	return exceptions.SegmentDefinition
+}
+
+func (p SegmentDefinitionError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.SegmentDefinition, nil)
+}
+
+type ConstraintError string
+
+func (p ConstraintError) Error() string {
+	return fmt.Sprintf("error code: %d - invalid version constraint %q", exceptions.Constraint, string(p))
+}
+
+func (p ConstraintError) Code() exceptions.Code {
+	return exceptions.Constraint
+}
+
+func (p ConstraintError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.Constraint, nil)
 }
@@ -0,0 +1,163 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	SchemeSemVer     = "semver"
+	SchemeCalVer     = "calver"
+	SchemeFourPart   = "fourpart"
+	SchemeMajorMinor = "majorminor"
+	SchemeCustom     = "custom"
+)
+
+const (
+	SegmentBuild    = "build"
+	SegmentRevision = "revision"
+)
+
+// Scheme defines how the core of a version (without pre-release) is parsed,
+// formatted and bumped.
+type Scheme interface {
+	// Name returns the name of the scheme as used in the configuration.
+	Name() string
+	// Segments returns the segment names, most significant first.
+	Segments() []string
+	// Parse splits a version core into its segments.
+	Parse(core string) ([]int, error)
+	// Format joins the segments into a version core.
+	Format(segments []int) string
+	// Bump returns the segments of the next version for a bump of the
+	// named segment.
+	Bump(segments []int, segment string) ([]int, error)
+}
+
+// Segment is a named numeric part of a version.
+type Segment struct {
+	Name string `mapstructure:"name" desc:"Name of the segment"`
+	// Keep preserves the value when a more significant segment is bumped,
+	// e.g. for continuously increasing build numbers.
+	Keep bool `mapstructure:"keep" desc:"Keep the value when a more significant segment is bumped"`
+}
+
+// SegmentScheme is a scheme of dot separated numeric segments. Bumping a
+// segment resets all less significant segments that are not kept.
+type SegmentScheme struct {
+	name     string
+	segments []Segment
+}
+
+// NewSegmentScheme creates a scheme with the given segments, most
+// significant first.
+func NewSegmentScheme(name string, segments []Segment) (*SegmentScheme, error) {
+	if len(segments) == 0 {
+		return nil, SegmentDefinitionError(name)
+	}
+
+	names := make(map[string]bool, len(segments))
+	for _, segment := range segments {
+		if segment.Name == "" || names[segment.Name] {
+			return nil, SegmentDefinitionError(name)
+		}
+		names[segment.Name] = true
+	}
+
+	s := &SegmentScheme{name: name, segments: make([]Segment, len(segments))}
+	copy(s.segments, segments)
+	return s, nil
+}
+
+// NewSemVer returns the default major.minor.patch scheme.
+func NewSemVer() *SegmentScheme {
+	s, _ := NewSegmentScheme(SchemeSemVer, []Segment{{Name: SegmentMajor}, {Name: SegmentMinor}, {Name: SegmentPatch}})
+	return s
+}
+
+// NewFourPart returns the major.minor.build.revision scheme used by .NET and
+// Windows installers.
+func NewFourPart() *SegmentScheme {
+	s, _ := NewSegmentScheme(SchemeFourPart, []Segment{{Name: SegmentMajor}, {Name: SegmentMinor}, {Name: SegmentBuild}, {Name: SegmentRevision}})
+	return s
+}
+
+// NewMajorMinor returns the major.minor scheme.
+func NewMajorMinor() *SegmentScheme {
+	s, _ := NewSegmentScheme(SchemeMajorMinor, []Segment{{Name: SegmentMajor}, {Name: SegmentMinor}})
+	return s
+}
+
+func (s *SegmentScheme) Name() string {
+	return s.name
+}
+
+func (s *SegmentScheme) Segments() []string {
+	names := make([]string, len(s.segments))
+	for i, segment := range s.segments {
+		names[i] = segment.Name
+	}
+	return names
+}
+
+func (s *SegmentScheme) Parse(core string) ([]int, error) {
+	return parseSegments(core, len(s.segments))
+}
+
+func (s *SegmentScheme) Format(segments []int) string {
+	return formatSegments(segments)
+}
+
+func (s *SegmentScheme) Bump(segments []int, segment string) ([]int, error) {
+	index := indexOf(s.Segments(), segment)
+	if index < 0 {
+		return nil, UnknownSegmentError(segment)
+	}
+
+	next := make([]int, len(segments))
+	copy(next, segments)
+	next[index]++
+	for i := index + 1; i < len(next); i++ {
+		if !s.segments[i].Keep {
+			next[i] = 0
+		}
+	}
+	return next, nil
+}
+
+// parseSegments parses exactly count dot separated, non-negative numbers
+// without leading zeros.
+func parseSegments(core string, count int) ([]int, error) {
+	parts := strings.Split(core, ".")
+	if len(parts) != count {
+		return nil, fmt.Errorf("expected %d segments in %q", count, core)
+	}
+
+	segments := make([]int, count)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || strconv.Itoa(n) != part {
+			return nil, fmt.Errorf("invalid segment %q in %q", part, core)
+		}
+		segments[i] = n
+	}
+	return segments, nil
+}
+
+func formatSegments(segments []int) string {
+	parts := make([]string, len(segments))
+	for i, n := range segments {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -5,22 +5,37 @@ import (
 	"github.com/spf13/afero"
 	"gotver/internal/constants"
 	"io"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	SegmentMajor = "major"
+	SegmentMinor = "minor"
+	SegmentPatch = "patch"
 )
 
 var v *Version
 
 type Version struct {
-	major               int
-	minor               int
-	patch               int
+	scheme              Scheme
+	segments            []int
+	preRelease          string
+	preReleaseID        string
+	bumpLimit           string
+	lineMajor           int
+	lineMinor           int
 	versionFilePath     string
 	versionFileName     string
 	lastVersionFileName string
 	lastVersion         string
+	deferWrite          bool
 	fs                  afero.Fs
+	workingDirectory    string
+	logger              *slog.Logger
 }
 
 func init() {
@@ -29,27 +44,72 @@ func init() {
 
 func New() *Version {
 	v := new(Version)
-	v.major = 0
-	v.minor = 0
-	v.patch = 0
+	v.scheme = NewSemVer()
+	v.segments = []int{0, 0, 0}
 	v.versionFilePath = ""
 	v.versionFileName = ""
 	v.lastVersionFileName = ".lastversion"
 	v.lastVersion = "0.0.0"
+	v.lineMajor = -1
+	v.lineMinor = -1
 	v.fs = afero.NewOsFs()
+	v.workingDirectory = "."
+	v.logger = slog.Default()
 	return v
 }
 
+func SetLogger(logger *slog.Logger) {
+	v.SetLogger(logger)
+}
+
+// SetLogger sets the logger the version decisions are logged to.
+func (v *Version) SetLogger(logger *slog.Logger) {
+	v.logger = logger
+}
+
+func SetFs(fs afero.Fs) {
+	v.SetFs(fs)
+}
+
+// SetFs sets the filesystem the version files are read from and written to.
+func (v *Version) SetFs(fs afero.Fs) {
+	v.fs = fs
+}
+
+func SetWorkingDirectory(dir string) {
+	v.SetWorkingDirectory(dir)
+}
+
+// SetWorkingDirectory sets the directory the project directory is searched
+// from, the current directory "." is used otherwise.
+func (v *Version) SetWorkingDirectory(dir string) {
+	v.workingDirectory = dir
+}
+
 // GetProjectDirectory returns the directory containing the .gotver folder.
 func GetProjectDirectory() (string, error) {
-	dir, err := os.Getwd()
+	return v.GetProjectDirectory()
+}
+
+func (v *Version) GetProjectDirectory() (string, error) {
+	return v.FindProjectDirectory(v.workingDirectory)
+}
+
+// FindProjectDirectory returns the first directory containing the .gotver
+// folder, starting at dir and walking up to the root.
+func FindProjectDirectory(dir string) (string, error) {
+	return v.FindProjectDirectory(dir)
+}
+
+func (v *Version) FindProjectDirectory(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
 	if err != nil {
 		return "", UnhandledError(err.Error())
 	}
 
 	for {
 		// Überprüfen Sie, ob das aktuelle Verzeichnis `.gotver` enthält.
-		if _, err := os.Stat(filepath.Join(dir, constants.ConfigFolderName)); !os.IsNotExist(err) {
+		if exists, _ := afero.DirExists(v.fs, filepath.Join(dir, constants.ConfigFolderName)); exists {
 			return filepath.Clean(dir), nil
 		}
 
@@ -76,28 +136,28 @@ func (v *Version) ReadVersion() error {
 	versionFilePath := filepath.Join(v.versionFilePath, v.versionFileName)
 	lastVersionFilePath := filepath.Join(v.versionFilePath, v.lastVersionFileName)
 
-	data, err := os.ReadFile(versionFilePath)
+	data, err := afero.ReadFile(v.fs, versionFilePath)
 	if err != nil {
-		return FileNotFoundError(versionFilePath)
+		return FileNotFoundError{versionFilePath, err}
 	}
 
-	_, err = fmt.Sscanf(string(data), "%d.%d.%d", &v.major, &v.minor, &v.patch)
-	if err != nil {
-		return FileFormatError(versionFilePath)
+	if err := v.parse(strings.TrimSpace(string(data))); err != nil {
+		return FileFormatError{versionFilePath, err}
 	}
+	v.logger.Debug("version file read", "file", versionFilePath, "version", v.ToString())
 
-	if _, err := os.Stat(lastVersionFilePath); os.IsNotExist(err) {
+	if exists, _ := afero.Exists(v.fs, lastVersionFilePath); !exists {
 		return nil
 	}
 
-	data, err = os.ReadFile(lastVersionFilePath)
+	data, err = afero.ReadFile(v.fs, lastVersionFilePath)
 	if err != nil {
-		return FileNotFoundError(lastVersionFilePath)
+		return FileNotFoundError{lastVersionFilePath, err}
 	}
 
 	_, err = fmt.Sscan(string(data), &v.lastVersion)
 	if err != nil {
-		return FileFormatError(versionFilePath)
+		return FileFormatError{lastVersionFilePath, err}
 	}
 
 	return nil
@@ -126,10 +186,9 @@ func (v *Version) SafeWriteVersion() error {
 	dir := filepath.Join(v.versionFilePath)
 	versionFilePath := filepath.Join(v.versionFilePath, v.versionFileName)
 
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		err := os.Mkdir(dir, os.ModePerm)
-		if err != nil {
-			return err
+	if exists, _ := afero.DirExists(v.fs, dir); !exists {
+		if err := v.fs.Mkdir(dir, os.ModePerm); err != nil {
+			return WriteOperationFailedError{dir, err}
 		}
 	}
 
@@ -149,7 +208,10 @@ func ToString() string {
 	return v.ToString()
 }
 func (v *Version) ToString() string {
-	return fmt.Sprintf("%d.%d.%d", v.major, v.minor, v.patch)
+	if v.preRelease != "" {
+		return v.scheme.Format(v.segments) + "-" + v.preRelease
+	}
+	return v.scheme.Format(v.segments)
 }
 
 func GetLastVersion() string {
@@ -163,13 +225,279 @@ func FromString(version string) error {
 	return v.FromString(version)
 }
 func (v *Version) FromString(version string) error {
-	_, err := fmt.Sscanf(version, "%d.%d.%d", &v.major, &v.minor, &v.patch)
-	if err != nil {
+	if err := v.parse(version); err != nil {
 		return InputValueError(version)
 	}
 	return nil
 }
 
+// parse reads a version of the form <core>[-prerelease], the core is
+// parsed by the scheme.
+func (v *Version) parse(version string) error {
+	core, preRelease, _ := strings.Cut(version, "-")
+
+	segments, err := v.scheme.Parse(core)
+	if err != nil {
+		return err
+	}
+
+	v.segments = segments
+	v.preRelease = preRelease
+	return nil
+}
+
+// SetScheme sets the versioning scheme. It has to be set before the version
+// is read.
+func SetScheme(scheme Scheme) {
+	v.SetScheme(scheme)
+}
+
+func (v *Version) SetScheme(scheme Scheme) {
+	v.scheme = scheme
+	v.segments = make([]int, len(scheme.Segments()))
+}
+
+// NewScheme returns the scheme with the given name. The format is only used
+// by CalVer, the segments only by custom schemes.
+func NewScheme(name string, format string, segments []Segment) (Scheme, error) {
+	switch name {
+	case "", SchemeSemVer:
+		return NewSemVer(), nil
+	case SchemeFourPart:
+		return NewFourPart(), nil
+	case SchemeMajorMinor:
+		return NewMajorMinor(), nil
+	case SchemeCalVer:
+		return NewCalVer(format, clock)
+	case SchemeCustom:
+		return NewSegmentScheme(name, segments)
+	default:
+		return nil, UnknownSchemeError(name)
+	}
+}
+
+// ParseTag returns the version of a tag created from a tag format like
+// constants.VersionTag, if the version is valid for the scheme.
+func ParseTag(tag string, format string) (string, bool) {
+	return v.ParseTag(tag, format)
+}
+
+func (v *Version) ParseTag(tag string, format string) (string, bool) {
+	prefix, suffix, _ := strings.Cut(format, "%s")
+	if len(tag) <= len(prefix)+len(suffix) || !strings.HasPrefix(tag, prefix) || !strings.HasSuffix(tag, suffix) {
+		return "", false
+	}
+
+	version := tag[len(prefix) : len(tag)-len(suffix)]
+	core, _, _ := strings.Cut(version, "-")
+	if _, err := v.scheme.Parse(core); err != nil {
+		return "", false
+	}
+	return version, true
+}
+
+// PreviousTag returns the tag of the highest version lower than current,
+// or an empty string if there is none. Tags not matching the format are
+// ignored.
+func PreviousTag(tags []string, current string, format string) string {
+	return v.PreviousTag(tags, current, format)
+}
+
+func (v *Version) PreviousTag(tags []string, current string, format string) string {
+	previousTag, previous := "", ""
+	for _, tag := range tags {
+		tagVersion, ok := v.ParseTag(tag, format)
+		if !ok {
+			continue
+		}
+
+		if result, err := Compare(tagVersion, current); err != nil || result >= 0 {
+			continue
+		}
+
+		if previous != "" {
+			if result, err := Compare(tagVersion, previous); err != nil || result <= 0 {
+				continue
+			}
+		}
+
+		previousTag, previous = tag, tagVersion
+	}
+
+	return previousTag
+}
+
+// LatestTag returns the tag with the highest version of the given format,
+// or an empty string if no tag matches the format.
+func LatestTag(tags []string, format string) string {
+	return v.LatestTag(tags, format)
+}
+
+func (v *Version) LatestTag(tags []string, format string) string {
+	latestTag, latest := "", ""
+	for _, tag := range tags {
+		tagVersion, ok := v.ParseTag(tag, format)
+		if !ok {
+			continue
+		}
+
+		if latest != "" {
+			if result, err := Compare(tagVersion, latest); err != nil || result <= 0 {
+				continue
+			}
+		}
+
+		latestTag, latest = tag, tagVersion
+	}
+
+	return latestTag
+}
+
+// GetSegments returns the segment names of the versioning scheme.
+func GetSegments() []string {
+	return v.GetSegments()
+}
+
+func (v *Version) GetSegments() []string {
+	return v.scheme.Segments()
+}
+
+// SetPreReleaseIdentifier sets the identifier (e.g. beta, rc) used for
+// pre-release bumps. An empty identifier produces final releases.
+func SetPreReleaseIdentifier(id string) {
+	v.SetPreReleaseIdentifier(id)
+}
+
+func (v *Version) SetPreReleaseIdentifier(id string) {
+	v.preReleaseID = id
+}
+
+// SetBumpLimit restricts bumps to the given segment and below. An empty
+// segment removes the restriction.
+func SetBumpLimit(segment string) {
+	v.SetBumpLimit(segment)
+}
+
+func (v *Version) SetBumpLimit(segment string) {
+	v.bumpLimit = segment
+}
+
+// SetVersionLine restricts bumps to a maintenance line such as `1.4`,
+// `1.4.x` or `1.x`, typically taken from the branch name. A leading `v` is
+// accepted.
+func SetVersionLine(line string) error {
+	return v.SetVersionLine(line)
+}
+
+func (v *Version) SetVersionLine(line string) error {
+	parts := strings.Split(strings.TrimPrefix(line, "v"), ".")
+	if len(parts) > 1 && parts[len(parts)-1] == "x" {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) == 0 || len(parts) > 2 {
+		return VersionLineError(line)
+	}
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil || major < 0 {
+		return VersionLineError(line)
+	}
+
+	minor := -1
+	if len(parts) == 2 {
+		minor, err = strconv.Atoi(parts[1])
+		if err != nil || minor < 0 {
+			return VersionLineError(line)
+		}
+	}
+
+	v.lineMajor = major
+	v.lineMinor = minor
+	return nil
+}
+
+func (v *Version) lineString() string {
+	if v.lineMinor < 0 {
+		return fmt.Sprintf("%d.x", v.lineMajor)
+	}
+	return fmt.Sprintf("%d.%d.x", v.lineMajor, v.lineMinor)
+}
+
+func (v *Version) inLine(segments []int) bool {
+	if v.lineMajor < 0 {
+		return true
+	}
+	if len(segments) < 2 {
+		return false
+	}
+	return segments[0] == v.lineMajor && (v.lineMinor < 0 || segments[1] == v.lineMinor)
+}
+
+func GetPreRelease() string {
+	return v.GetPreRelease()
+}
+
+func (v *Version) GetPreRelease() string {
+	return v.preRelease
+}
+
+// SetDeferWrite keeps bumped versions in memory until WriteVersion is
+// called, e.g. to run hooks between computing and writing the version.
+func SetDeferWrite(deferWrite bool) {
+	v.SetDeferWrite(deferWrite)
+}
+
+func (v *Version) SetDeferWrite(deferWrite bool) {
+	v.deferWrite = deferWrite
+}
+
+// GetFiles returns the paths of the version and last version files.
+func GetFiles() []string {
+	return v.GetFiles()
+}
+
+func (v *Version) GetFiles() []string {
+	return []string{
+		filepath.Join(v.versionFilePath, v.versionFileName),
+		filepath.Join(v.versionFilePath, v.lastVersionFileName),
+	}
+}
+
+// SetLastVersion sets the version the current version was bumped from.
+func SetLastVersion(lastVersion string) error {
+	return v.SetLastVersion(lastVersion)
+}
+
+func (v *Version) SetLastVersion(lastVersion string) error {
+	core, _, _ := strings.Cut(lastVersion, "-")
+	if _, err := v.scheme.Parse(core); err != nil {
+		return InputValueError(lastVersion)
+	}
+	v.lastVersion = lastVersion
+	return nil
+}
+
+// WriteVersionFiles writes the version and the last version as they are.
+// Unlike WriteVersion the last version is not taken from the version file.
+func WriteVersionFiles() error {
+	return v.WriteVersionFiles()
+}
+
+func (v *Version) WriteVersionFiles() error {
+	versionFilePath := filepath.Join(v.versionFilePath, v.versionFileName)
+	lastVersionFilePath := filepath.Join(v.versionFilePath, v.lastVersionFileName)
+
+	if err := afero.WriteFile(v.fs, versionFilePath, []byte(v.ToString()), os.ModePerm); err != nil {
+		return WriteOperationFailedError{versionFilePath, err}
+	}
+	if err := afero.WriteFile(v.fs, lastVersionFilePath, []byte(v.lastVersion), os.ModePerm); err != nil {
+		return WriteOperationFailedError{lastVersionFilePath, err}
+	}
+	v.logger.Debug("version files written", "version", v.ToString(), "lastVersion", v.lastVersion)
+
+	return nil
+}
+
 func WriteVersion() error {
 	return v.WriteVersion()
 }
@@ -178,31 +506,33 @@ func (v *Version) WriteVersion() error {
 	versionFilePath := filepath.Join(v.versionFilePath, v.versionFileName)
 	lastVersionFilePath := filepath.Join(v.versionFilePath, v.lastVersionFileName)
 
-	if _, err := os.Stat(versionFilePath); !os.IsNotExist(err) {
-		source, err := os.Open(versionFilePath)
+	if exists, _ := afero.Exists(v.fs, versionFilePath); exists {
+		source, err := v.fs.Open(versionFilePath)
 		if err != nil {
-			log.Fatal(err)
+			return WriteOperationFailedError{lastVersionFilePath, err}
 		}
 		defer source.Close()
 
 		// Zieldatei erstellen
-		destination, err := os.Create(lastVersionFilePath)
+		destination, err := v.fs.Create(lastVersionFilePath)
 		if err != nil {
-			log.Fatal(err)
+			return WriteOperationFailedError{lastVersionFilePath, err}
 		}
 		defer destination.Close()
 
 		// Inhalt kopieren
 		_, err = io.Copy(destination, source)
 		if err != nil {
-			log.Fatal(err)
+			return WriteOperationFailedError{lastVersionFilePath, err}
 		}
+		v.logger.Debug("file written", "file", lastVersionFilePath)
 	}
 
-	err := os.WriteFile(versionFilePath, []byte(v.ToString()), os.ModePerm)
+	err := afero.WriteFile(v.fs, versionFilePath, []byte(v.ToString()), os.ModePerm)
 	if err != nil {
 		return WriteOperationFailedError{versionFilePath, err}
 	}
+	v.logger.Debug("file written", "file", versionFilePath, "version", v.ToString())
 
 	return nil
 }
@@ -211,31 +541,162 @@ func BumpMajor() error {
 	return v.BumpMajor()
 }
 func (v *Version) BumpMajor() error {
-	v.lastVersion = v.ToString()
-	v.major++
-	v.minor = 0
-	v.patch = 0
-
-	return v.WriteVersion()
+	return v.bump(v.LevelSegment(SegmentMajor))
 }
 
 func BumpMinor() error {
 	return v.BumpMinor()
 }
 func (v *Version) BumpMinor() error {
-	v.lastVersion = v.ToString()
-	v.minor++
-	v.patch = 0
-
-	return v.WriteVersion()
+	return v.bump(v.LevelSegment(SegmentMinor))
 }
 
 func BumpPatch() error {
 	return v.BumpPatch()
 }
 func (v *Version) BumpPatch() error {
+	return v.bump(v.LevelSegment(SegmentPatch))
+}
+
+// Bump increments the named segment of the versioning scheme.
+func Bump(segment string) error {
+	return v.Bump(segment)
+}
+
+func (v *Version) Bump(segment string) error {
+	return v.bump(segment)
+}
+
+// LevelSegment returns the segment of the scheme a major, minor or patch
+// bump increments, e.g. build for a patch of the fourpart scheme. Other
+// levels are returned unchanged.
+func LevelSegment(level string) string {
+	return v.LevelSegment(level)
+}
+
+func (v *Version) LevelSegment(level string) string {
+	switch level {
+	case SegmentMajor:
+		return v.segmentAt(0)
+	case SegmentMinor:
+		return v.segmentAt(1)
+	case SegmentPatch:
+		return v.segmentAt(2)
+	default:
+		return level
+	}
+}
+
+// segmentAt returns the name of the segment at the position, falling back
+// to the least significant segment for schemes with fewer segments.
+func (v *Version) segmentAt(position int) string {
+	segments := v.scheme.Segments()
+	if position >= len(segments) {
+		position = len(segments) - 1
+	}
+	return segments[position]
+}
+
+func (v *Version) bump(segment string) error {
+	if indexOf(v.scheme.Segments(), segment) < 0 {
+		return UnknownSegmentError(segment)
+	}
+
+	if !v.withinLimit(segment) {
+		return BumpNotAllowedError(segment)
+	}
+
+	if !v.inLine(v.segments) {
+		return OutsideVersionLineError{v.ToString(), v.lineString()}
+	}
+
+	next, err := v.scheme.Bump(v.segments, segment)
+	if err != nil {
+		return err
+	}
+
+	// A pre-release keeps its core unless the bump is more significant than
+	// the one pending in the core.
+	keepCore := v.preRelease != "" && !v.raisesPending(segment)
+	if !keepCore && !v.inLine(next) {
+		return OutsideVersionLineError{fmt.Sprintf("%s bump", segment), v.lineString()}
+	}
+
 	v.lastVersion = v.ToString()
-	v.patch++
 
+	switch {
+	case keepCore && v.preReleaseID != "":
+		// Count up within the same identifier or switch to the new one.
+		v.preRelease = v.nextPreRelease()
+		return v.write()
+	case keepCore:
+		// Finalize the pending pre-release.
+		v.preRelease = ""
+		return v.write()
+	}
+
+	v.segments = next
+	v.preRelease = ""
+	if v.preReleaseID != "" {
+		v.preRelease = v.preReleaseID + ".1"
+	}
+
+	return v.write()
+}
+
+// raisesPending reports whether a bump of the segment is more significant
+// than the bump pending in the core of a pre-release, e.g. a minor bump of
+// 1.2.1-beta.1 whose patch bump is pending. The pending segment is the least
+// significant one that is not zero and not kept.
+func (v *Version) raisesPending(segment string) bool {
+	scheme, ok := v.scheme.(*SegmentScheme)
+	if !ok {
+		// Calendar versions are defined by the date, not by a segment.
+		return false
+	}
+
+	pending := 0
+	for i, n := range v.segments {
+		if n != 0 && !scheme.segments[i].Keep {
+			pending = i
+		}
+	}
+	return indexOf(scheme.Segments(), segment) < pending
+}
+
+// write writes the bumped version unless writing is deferred.
+func (v *Version) write() error {
+	v.logger.Debug("version bumped", "from", v.lastVersion, "to", v.ToString())
+	if v.deferWrite {
+		return nil
+	}
 	return v.WriteVersion()
 }
+
+// withinLimit reports whether the segment is not more significant than the
+// bump limit. Limits unknown to the scheme do not restrict bumps.
+func (v *Version) withinLimit(segment string) bool {
+	if v.bumpLimit == "" {
+		return true
+	}
+
+	segments := v.scheme.Segments()
+	limit := indexOf(segments, v.bumpLimit)
+	if limit < 0 {
+		return true
+	}
+	return indexOf(segments, segment) >= limit
+}
+
+func (v *Version) nextPreRelease() string {
+	id, number, found := strings.Cut(v.preRelease, ".")
+	if !found || id != v.preReleaseID {
+		return v.preReleaseID + ".1"
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return v.preReleaseID + ".1"
+	}
+	return fmt.Sprintf("%s.%d", id, n+1)
+}
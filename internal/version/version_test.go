@@ -0,0 +1,153 @@
+package version
+
+import (
+	"errors"
+	"github.com/spf13/afero"
+	"gotver/internal/exceptions"
+	"io/fs"
+	"testing"
+)
+
+// newVersion returns a version of the scheme that is never written.
+func newVersion(t *testing.T, scheme Scheme, version string) *Version {
+	t.Helper()
+
+	v := New()
+	v.SetScheme(scheme)
+	v.SetDeferWrite(true)
+	if err := v.FromString(version); err != nil {
+		t.Fatalf("FromString(%q) error = %v", version, err)
+	}
+	return v
+}
+
+func TestLevelBump(t *testing.T) {
+	tests := []struct {
+		scheme  Scheme
+		version string
+		bump    func(v *Version) error
+		want    string
+	}{
+		{NewSemVer(), "1.2.3", (*Version).BumpMajor, "2.0.0"},
+		{NewSemVer(), "1.2.3", (*Version).BumpMinor, "1.3.0"},
+		{NewSemVer(), "1.2.3", (*Version).BumpPatch, "1.2.4"},
+		{NewFourPart(), "1.2.3.4", (*Version).BumpMinor, "1.3.0.0"},
+		{NewFourPart(), "1.2.3.4", (*Version).BumpPatch, "1.2.4.0"},
+		{NewMajorMinor(), "1.2", (*Version).BumpMajor, "2.0"},
+		{NewMajorMinor(), "1.2", (*Version).BumpPatch, "1.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme.Name()+"/"+tt.want, func(t *testing.T) {
+			v := newVersion(t, tt.scheme, tt.version)
+			if err := tt.bump(v); err != nil {
+				t.Fatalf("bump error = %v", err)
+			}
+			if got := v.ToString(); got != tt.want {
+				t.Errorf("bump of %s = %s, want %s", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevelSegment(t *testing.T) {
+	v := newVersion(t, NewFourPart(), "1.0.0.0")
+	for level, want := range map[string]string{
+		SegmentMajor:    SegmentMajor,
+		SegmentMinor:    SegmentMinor,
+		SegmentPatch:    SegmentBuild,
+		SegmentRevision: SegmentRevision,
+	} {
+		if got := v.LevelSegment(level); got != want {
+			t.Errorf("LevelSegment(%q) = %q, want %q", level, got, want)
+		}
+	}
+}
+
+func TestBumpPreRelease(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		id      string
+		segment string
+		want    string
+	}{
+		{"start", "1.2.0", "beta", SegmentPatch, "1.2.1-beta.1"},
+		{"count up", "1.2.1-beta.1", "beta", SegmentPatch, "1.2.1-beta.2"},
+		{"count up for the pending minor", "1.3.0-beta.1", "beta", SegmentPatch, "1.3.0-beta.2"},
+		{"raise to minor", "1.2.1-beta.1", "beta", SegmentMinor, "1.3.0-beta.1"},
+		{"raise to major", "1.3.0-beta.2", "beta", SegmentMajor, "2.0.0-beta.1"},
+		{"keep major", "2.0.0-beta.2", "beta", SegmentMinor, "2.0.0-beta.3"},
+		{"switch identifier", "1.2.1-alpha.3", "beta", SegmentPatch, "1.2.1-beta.1"},
+		{"raise and switch identifier", "1.2.1-alpha.3", "beta", SegmentMinor, "1.3.0-beta.1"},
+		{"finalize", "1.2.1-beta.2", "", SegmentPatch, "1.2.1"},
+		{"finalize the pending minor", "1.3.0-beta.2", "", SegmentPatch, "1.3.0"},
+		{"finalize raised to minor", "1.2.1-beta.2", "", SegmentMinor, "1.3.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newVersion(t, NewSemVer(), tt.version)
+			v.SetPreReleaseIdentifier(tt.id)
+			if err := v.Bump(tt.segment); err != nil {
+				t.Fatalf("Bump(%q) error = %v", tt.segment, err)
+			}
+			if got := v.ToString(); got != tt.want {
+				t.Errorf("Bump(%q) of %s = %s, want %s", tt.segment, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBumpErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   string
+		segment string
+		want    exceptions.Code
+	}{
+		{"unknown segment", "", "build", exceptions.UnknownSegment},
+		{"unknown segment with a limit", SegmentMinor, "build", exceptions.UnknownSegment},
+		{"beyond the limit", SegmentMinor, SegmentMajor, exceptions.BumpNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newVersion(t, NewSemVer(), "1.2.3")
+			v.SetBumpLimit(tt.limit)
+			if err := v.Bump(tt.segment); exceptions.CodeOf(err) != tt.want {
+				t.Errorf("Bump(%q) error = %v, want code %d", tt.segment, err, tt.want)
+			}
+			if got := v.ToString(); got != "1.2.3" {
+				t.Errorf("failed bump changed the version to %s", got)
+			}
+		})
+	}
+}
+
+func TestReadVersionErrors(t *testing.T) {
+	memFs := afero.NewMemMapFs()
+	if err := afero.WriteFile(memFs, "/project/.gitver/.version", []byte("1.x"), 0o644); err != nil {
+		t.Fatalf("write version: %v", err)
+	}
+
+	read := func(name string) error {
+		v := New()
+		v.SetFs(memFs)
+		v.SetFilePath("/project/.gitver")
+		v.SetFileName(name)
+		return v.ReadVersion()
+	}
+
+	err := read(".missing")
+	var notFound FileNotFoundError
+	if !errors.As(err, &notFound) || !errors.Is(err, fs.ErrNotExist) || !errors.Is(err, exceptions.ErrNotFound) {
+		t.Errorf("ReadVersion() of a missing file error = %v, want a FileNotFoundError caused by fs.ErrNotExist", err)
+	}
+
+	err = read(".version")
+	var format FileFormatError
+	if !errors.As(err, &format) || !errors.Is(err, exceptions.ErrInvalidInput) || !errors.Is(err, format.error) {
+		t.Errorf("ReadVersion() of an invalid file error = %v, want a FileFormatError with its cause", err)
+	}
+}
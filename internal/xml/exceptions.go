@@ -1 +1,57 @@
 package xml
+
+import (
+	"fmt"
+	"gotver/internal/exceptions"
+)
+
+type ReadError struct {
+	file  string
+	error error
+}
+
+func (p ReadError) Error() string {
+	return fmt.Sprintf("error code: %d - %q could not be read: %v", exceptions.XMLRead, p.file, p.error)
+}
+
+func (p ReadError) Code() exceptions.Code {
+	return exceptions.XMLRead
+}
+
+func (p ReadError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.XMLRead, p.error)
+}
+
+type WriteError struct {
+	file  string
+	error error
+}
+
+func (p WriteError) Error() string {
+	return fmt.Sprintf("error code: %d - %q could not be written: %v", exceptions.XMLWrite, p.file, p.error)
+}
+
+func (p WriteError) Code() exceptions.Code {
+	return exceptions.XMLWrite
+}
+
+func (p WriteError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.XMLWrite, p.error)
+}
+
+type ElementNotFoundError struct {
+	file  string
+	xPath string
+}
+
+func (p ElementNotFoundError) Error() string {
+	return fmt.Sprintf("error code: %d - element %q not found in %q", exceptions.XMLElementNotFound, p.xPath, p.file)
+}
+
+func (p ElementNotFoundError) Code() exceptions.Code {
+	return exceptions.XMLElementNotFound
+}
+
+func (p ElementNotFoundError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.XMLElementNotFound, nil)
+}
@@ -1,35 +1,51 @@
 package xml
 
 import (
-	"fmt"
 	"github.com/beevik/etree"
+	"github.com/spf13/afero"
+	"os"
+	"strings"
 )
 
-const (
-	errorCode3000 = iota + 3000
-	errorCode3001 = iota + 3000
-	errorCode3002 = iota + 3000
-)
+// GetVersion returns the text of the element at the xml path, e.g. the
+// version of a pom.xml.
+func GetVersion(fs afero.Fs, filePath string, xPath string) (string, error) {
+	data, err := afero.ReadFile(fs, filePath)
+	if err != nil {
+		return "", ReadError{filePath, err}
+	}
 
-const (
-	errorMessage         = "error code: %d - %s"
-	errorElementNotFound = "element not found"
-)
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return "", ReadError{filePath, err}
+	}
+
+	element := doc.FindElement(xPath)
+	if element == nil {
+		return "", ElementNotFoundError{filePath, xPath}
+	}
+
+	return strings.TrimSpace(element.Text()), nil
+}
 
 // SetVersion set version in a xml path
-func SetVersion(filePath string, xPath string, value string) error {
+func SetVersion(fs afero.Fs, filePath string, xPath string, value string) error {
+
+	data, err := afero.ReadFile(fs, filePath)
+	if err != nil {
+		return ReadError{filePath, err}
+	}
 
 	doc := etree.NewDocument()
-	if err := doc.ReadFromFile(filePath); err != nil {
-		return fmt.Errorf(errorMessage, errorCode3000, err)
+	if err := doc.ReadFromBytes(data); err != nil {
+		return ReadError{filePath, err}
 	}
 
 	// Suchen Sie das Element basierend auf einem Pfad.
 	// Im Beispiel suchen wir nach dem ersten "version"-Element unter "project".
 	element := doc.FindElement(xPath)
 	if element == nil {
-		// ToDo ErrorCode Erstellen
-		return fmt.Errorf(errorMessage, errorCode3002, errorElementNotFound)
+		return ElementNotFoundError{filePath, xPath}
 	}
 
 	// Setzen Sie den Wert des gefundenen Elements.
@@ -37,8 +53,12 @@ func SetVersion(filePath string, xPath string, value string) error {
 
 	// Speichern Sie das aktualisierte Dokument zurück in eine Datei.
 	doc.Indent(2)
-	if err := doc.WriteToFile(filePath); err != nil {
-		return fmt.Errorf(errorMessage, errorCode3001, err)
+	data, err = doc.WriteToBytes()
+	if err != nil {
+		return WriteError{filePath, err}
+	}
+	if err := afero.WriteFile(fs, filePath, data, os.ModePerm); err != nil {
+		return WriteError{filePath, err}
 	}
 
 	return nil
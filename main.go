@@ -3,8 +3,11 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 */
 package main
 
-import "gotver/cmd"
+import (
+	"gotver/cmd"
+	"os"
+)
 
 func main() {
-	cmd.Execute()
+	os.Exit(cmd.Execute())
 }
@@ -0,0 +1,146 @@
+package gitver
+
+import (
+	"context"
+	"fmt"
+	"gotver/internal/constants"
+	"gotver/internal/gitops"
+	"gotver/internal/notes"
+	"gotver/internal/version"
+	"strings"
+)
+
+// Changelog are the categorised commits of a version.
+type (
+	Changelog        = notes.Notes
+	ChangelogSection = notes.Section
+	ChangelogEntry   = notes.Entry
+)
+
+// Changelog formats supported by RenderChangelog.
+const (
+	FormatMarkdown = notes.FormatMarkdown
+	FormatJSON     = notes.FormatJSON
+	FormatText     = notes.FormatText
+)
+
+// ChangelogOptions configure Changelog.
+type ChangelogOptions struct {
+	// Version selects a tagged version. Without it the current version is
+	// used, if it is not tagged yet the commits since the previous version
+	// tag are used.
+	Version string
+	// Range selects a tag range like v1.3.0..v1.4.0 instead of a version.
+	// Both tags must exist.
+	Range string
+}
+
+// Changelog collects the commits between the version tag and the tag of the
+// previous version, a version or a release tag.
+func (p *Project) Changelog(ctx context.Context, options ChangelogOptions) (Changelog, error) {
+	if err := ctx.Err(); err != nil {
+		return Changelog{}, err
+	}
+
+	if options.Version != "" && options.Range != "" {
+		return Changelog{}, InvalidOptionsError("a version and a range cannot be combined")
+	}
+
+	if err := p.repository(); err != nil {
+		return Changelog{}, err
+	}
+
+	var tag, previousTag, current, previous string
+
+	if options.Range != "" {
+		from, to, found := strings.Cut(options.Range, "..")
+		if !found || to == "" {
+			return Changelog{}, InvalidOptionsError(fmt.Sprintf("invalid tag range %q, use from..to", options.Range))
+		}
+		for _, t := range []string{from, to} {
+			if t != "" && !p.git.HasTag(t) {
+				return Changelog{}, gitops.TagNotFoundError(t)
+			}
+		}
+		tag, previousTag = to, from
+
+		current = to
+		if parsed, ok := p.tagVersion(to); ok {
+			current = parsed
+		}
+		previous, _ = p.tagVersion(from)
+	} else {
+		current = options.Version
+		if current == "" {
+			v, err := p.readVersion()
+			if err != nil {
+				return Changelog{}, err
+			}
+			current = v.ToString()
+		}
+
+		tag = fmt.Sprintf(constants.VersionTag, current)
+		switch {
+		case p.git.HasTag(tag):
+		case options.Version != "":
+			return Changelog{}, gitops.TagNotFoundError(tag)
+		default:
+			p.logger.Debug("version is not tagged, using HEAD", "version", current)
+			tag = ""
+		}
+
+		tags, err := p.git.GetTags()
+		if err != nil {
+			return Changelog{}, err
+		}
+		previousTag, previous = p.previousTag(tags, current)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return Changelog{}, err
+	}
+
+	commits, err := p.git.GetCommitsBetweenTags(tag, previousTag)
+	if err != nil {
+		return Changelog{}, err
+	}
+
+	c := notes.Build(current, commits)
+	c.Tag = tag
+	c.PreviousTag = previousTag
+	c.PreviousVersion = previous
+
+	return c, nil
+}
+
+// previousTag returns the version or release tag of the highest version
+// lower than current and that version. The version tag is preferred if both
+// exist, empty strings are returned if there is none.
+func (p *Project) previousTag(tags []string, current string) (string, string) {
+	tag := p.version.PreviousTag(tags, current, constants.VersionTag)
+	previous, _ := p.version.ParseTag(tag, constants.VersionTag)
+
+	releaseTag := p.version.PreviousTag(tags, current, constants.ReleaseTag)
+	release, ok := p.version.ParseTag(releaseTag, constants.ReleaseTag)
+	if !ok {
+		return tag, previous
+	}
+	if result, err := version.Compare(release, previous); previous == "" || (err == nil && result > 0) {
+		return releaseTag, release
+	}
+	return tag, previous
+}
+
+// tagVersion returns the version of a version or release tag.
+func (p *Project) tagVersion(tag string) (string, bool) {
+	if parsed, ok := p.version.ParseTag(tag, constants.VersionTag); ok {
+		return parsed, true
+	}
+	return p.version.ParseTag(tag, constants.ReleaseTag)
+}
+
+// RenderChangelog renders the changelog with the built-in template of the
+// format.
+func RenderChangelog(c Changelog, format string) (string, error) {
+	return notes.Render(c, format, "")
+}
@@ -0,0 +1,102 @@
+package gitver
+
+import (
+	"fmt"
+	"gotver/internal/exceptions"
+	"gotver/internal/gitops"
+)
+
+// Error kinds. Every gitver error unwraps to one of them, so they can be
+// tested with errors.Is.
+var (
+	ErrInternal        = exceptions.ErrInternal
+	ErrInvalidInput    = exceptions.ErrInvalidInput
+	ErrInvalidConfig   = exceptions.ErrInvalidConfig
+	ErrNotFound        = exceptions.ErrNotFound
+	ErrAlreadyExists   = exceptions.ErrAlreadyExists
+	ErrNotAllowed      = exceptions.ErrNotAllowed
+	ErrIO              = exceptions.ErrIO
+	ErrGit             = exceptions.ErrGit
+	ErrDirtyRepository = exceptions.ErrDirtyRepository
+	ErrRemote          = exceptions.ErrRemote
+	ErrHook            = exceptions.ErrHook
+	ErrNoChange        = exceptions.ErrNoChange
+)
+
+// DirtyRepositoryError is returned when a commit or tag is requested while
+// the working tree has uncommitted changes.
+type DirtyRepositoryError = gitops.DirtyRepositoryError
+
+// Code is the stable number of a gitver error.
+type Code = exceptions.Code
+
+// CodeOf returns the code of the first gitver error in the chain of err, or
+// 0 if there is none.
+func CodeOf(err error) Code {
+	return exceptions.CodeOf(err)
+}
+
+type ConfigError struct {
+	dir   string
+	error error
+}
+
+func (p ConfigError) Error() string {
+	return fmt.Sprintf("error code: %d - configuration of project %q could not be read: %v", exceptions.ProjectConfig, p.dir, p.error)
+}
+
+func (p ConfigError) Code() exceptions.Code {
+	return exceptions.ProjectConfig
+}
+
+func (p ConfigError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.ProjectConfig, p.error)
+}
+
+type RepositoryError struct {
+	dir   string
+	error error
+}
+
+func (p RepositoryError) Error() string {
+	return fmt.Sprintf("error code: %d - git repository of project %q could not be opened: %v", exceptions.ProjectRepository, p.dir, p.error)
+}
+
+func (p RepositoryError) Code() exceptions.Code {
+	return exceptions.ProjectRepository
+}
+
+func (p RepositoryError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.ProjectRepository, p.error)
+}
+
+// NoChangesError is returned when the commits since the last release do not
+// require a new version.
+type NoChangesError string
+
+func (p NoChangesError) Error() string {
+	return fmt.Sprintf("error code: %d - no new version required since %q", exceptions.NoChanges, string(p))
+}
+
+func (p NoChangesError) Code() exceptions.Code {
+	return exceptions.NoChanges
+}
+
+func (p NoChangesError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.NoChanges, nil)
+}
+
+// InvalidOptionsError is returned when the options of a call are invalid.
+type InvalidOptionsError string
+
+func (p InvalidOptionsError) Error() string {
+	return fmt.Sprintf("error code: %d - invalid options: %s", exceptions.InvalidOptions, string(p))
+}
+
+func (p InvalidOptionsError) Code() exceptions.Code {
+	return exceptions.InvalidOptions
+}
+
+func (p InvalidOptionsError) Unwrap() []error {
+	return exceptions.Unwrap(exceptions.InvalidOptions, nil)
+}
@@ -0,0 +1,471 @@
+// Package gitver is the library API of gitver. A Project bundles the
+// configuration, the version state and the git repository of a project
+// directory, so several projects can be handled in one process.
+//
+// Lifecycle hooks and forge publishing are features of the command line
+// tool and are not run by the library.
+package gitver
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"gotver/internal/analyzer"
+	"gotver/internal/config"
+	"gotver/internal/constants"
+	"gotver/internal/gitops"
+	"gotver/internal/policy"
+	"gotver/internal/version"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Project is a directory containing a .gitver folder.
+type Project struct {
+	dir     string
+	config  config.Config
+	version *version.Version
+	git     *gitops.GitOps
+	gitRead bool
+	logger  *slog.Logger
+	fs      afero.Fs
+	clock   func() time.Time
+}
+
+// Option configures a Project.
+type Option func(*Project)
+
+// WithLogger sets the logger decisions are logged to, slog.Default() is used
+// otherwise.
+func WithLogger(logger *slog.Logger) Option {
+	return func(p *Project) {
+		p.logger = logger
+	}
+}
+
+// WithFs sets the filesystem the configuration and the version files are
+// read from and written to, the operating system filesystem is used
+// otherwise. The git repository is always read from disk.
+func WithFs(fs afero.Fs) Option {
+	return func(p *Project) {
+		p.fs = fs
+	}
+}
+
+// WithClock sets the clock the dates of the calver scheme are taken from,
+// time.Now is used otherwise.
+func WithClock(now func() time.Time) Option {
+	return func(p *Project) {
+		p.clock = now
+	}
+}
+
+// State is the version state of a project.
+type State struct {
+	Version     string
+	LastVersion string
+	PreRelease  string
+	Scheme      string
+	// Tagged reports whether the version tag exists. It is false when the
+	// project is not a git repository.
+	Tagged bool
+}
+
+// BumpOptions configure Next and Bump.
+type BumpOptions struct {
+	// Segment is the segment to bump, e.g. major, minor, patch or a custom
+	// segment of the scheme. For schemes without them major, minor and
+	// patch bump the segment at their position, e.g. build for a patch of
+	// the fourpart scheme. Without a segment it is detected from the commits
+	// since the last tag.
+	Segment string
+	// Commit commits the version files and tags the commit.
+	Commit bool
+	// Push pushes the branch and the version tag, it implies Commit.
+	Push bool
+	// Amend amends the commit to the previous commit.
+	Amend bool
+}
+
+// BumpResult describes a version bump.
+type BumpResult struct {
+	PreviousVersion string
+	Version         string
+	Segment         string
+	// Detected reports whether the segment was detected from commits.
+	Detected bool
+	// Commits is the number of commits analyzed to detect the segment.
+	Commits int
+	// Files are the files written by Bump.
+	Files     []string
+	Tag       string
+	Committed bool
+	Pushed    bool
+}
+
+// ReleaseOptions configure Release.
+type ReleaseOptions struct {
+	// Push pushes the release tag to origin.
+	Push bool
+}
+
+// ReleaseResult describes a release.
+type ReleaseResult struct {
+	Version string
+	Tag     string
+	// Created is false if the release tag already existed.
+	Created bool
+	Pushed  bool
+}
+
+// Open opens the project containing path. The project directory is the
+// first directory containing a .gitver folder, starting at path.
+func Open(path string, options ...Option) (*Project, error) {
+	p := &Project{logger: slog.Default(), fs: afero.NewOsFs(), clock: time.Now}
+	for _, option := range options {
+		option(p)
+	}
+
+	finder := version.New()
+	finder.SetFs(p.fs)
+	dir, err := finder.FindProjectDirectory(path)
+	if err != nil {
+		return nil, err
+	}
+	p.dir = dir
+
+	settings := viper.New()
+	settings.SetFs(p.fs)
+	settings.SetConfigName(constants.ConfigName)
+	settings.SetConfigType(constants.ConfigType)
+	settings.AddConfigPath(filepath.Join(dir, constants.ConfigFolderName))
+	if err := settings.ReadInConfig(); err != nil {
+		return nil, ConfigError{dir, err}
+	}
+	if p.config, err = config.Decode(settings.AllSettings()); err != nil {
+		return nil, ConfigError{dir, err}
+	}
+	if p.config.SchemaVersion > config.SchemaVersion {
+		return nil, ConfigError{dir, config.NewerSchemaError(p.config.SchemaVersion)}
+	}
+	if p.config.SchemaVersion < config.SchemaVersion {
+		p.logger.Warn("configuration is outdated, run gitver config migrate", "dir", dir,
+			"schemaVersion", p.config.SchemaVersion, "current", config.SchemaVersion)
+	}
+
+	p.git = gitops.New()
+	p.git.SetLogger(p.logger)
+	p.git.SetRepositoryPath(dir)
+	p.git.SetFirstParent(p.config.Commits.FirstParent)
+	p.git.SetMergesOnly(p.config.Commits.MergesOnly)
+
+	if p.version, err = p.readVersion(); err != nil {
+		return nil, err
+	}
+
+	p.logger.Debug("project opened", "dir", dir, "version", p.version.ToString())
+	return p, nil
+}
+
+// Dir returns the project directory.
+func (p *Project) Dir() string {
+	return p.dir
+}
+
+// Current returns the version state of the project.
+func (p *Project) Current(ctx context.Context) (State, error) {
+	if err := ctx.Err(); err != nil {
+		return State{}, err
+	}
+
+	v, err := p.readVersion()
+	if err != nil {
+		return State{}, err
+	}
+	p.version = v
+
+	state := State{
+		Version:     v.ToString(),
+		LastVersion: v.GetLastVersion(),
+		PreRelease:  v.GetPreRelease(),
+		Scheme:      p.config.Scheme.Name,
+	}
+
+	if err := p.repository(); err == nil {
+		state.Tagged = p.git.HasTag(fmt.Sprintf(constants.VersionTag, state.Version))
+	}
+
+	return state, nil
+}
+
+// Next returns the version a Bump with the same options would create,
+// without writing anything.
+func (p *Project) Next(ctx context.Context, options BumpOptions) (BumpResult, error) {
+	_, result, err := p.plan(ctx, options)
+	return result, err
+}
+
+// Bump bumps the version, writes the version files and, if requested,
+// commits, tags and pushes the change. If writing or a git operation fails,
+// the tag, the commit and the version files are rolled back, unless the
+// branch is already pushed.
+func (p *Project) Bump(ctx context.Context, options BumpOptions) (BumpResult, error) {
+	commit := options.Commit || options.Push
+	if commit {
+		if err := p.requireClean(); err != nil {
+			return BumpResult{}, err
+		}
+	}
+
+	v, result, err := p.plan(ctx, options)
+	if err != nil {
+		return BumpResult{}, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return BumpResult{}, err
+	}
+
+	tx := p.newTransaction()
+	tx.snapshot(v.GetFiles()...)
+	if err := v.WriteVersion(); err != nil {
+		return BumpResult{}, tx.fail(err)
+	}
+	result.Files = v.GetFiles()
+
+	if !commit {
+		p.version = v
+		return result, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return BumpResult{}, tx.fail(err)
+	}
+
+	if err := tx.recordHead(); err != nil {
+		return BumpResult{}, tx.fail(err)
+	}
+	if _, err := p.git.Add(); err != nil {
+		return BumpResult{}, tx.fail(err)
+	}
+	if err := p.git.Commit(fmt.Sprintf(constants.CommitMessage, result.PreviousVersion, result.Version), options.Amend); err != nil {
+		return BumpResult{}, tx.fail(err)
+	}
+	result.Committed = true
+
+	tag := fmt.Sprintf(constants.VersionTag, result.Version)
+	if err := p.git.CreateTag(tag, constants.TagMessage); err != nil {
+		return BumpResult{}, tx.fail(err)
+	}
+	tx.tags = append(tx.tags, tag)
+	result.Tag = tag
+
+	if !options.Push {
+		p.version = v
+		return result, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return BumpResult{}, tx.fail(err)
+	}
+
+	if err := p.git.Push(); err != nil {
+		return BumpResult{}, tx.fail(err)
+	}
+	p.version = v
+	if err := p.git.PushTag(tag); err != nil {
+		return result, err
+	}
+	result.Pushed = true
+
+	return result, nil
+}
+
+// Release tags the current version as release.
+func (p *Project) Release(ctx context.Context, options ReleaseOptions) (ReleaseResult, error) {
+	if err := ctx.Err(); err != nil {
+		return ReleaseResult{}, err
+	}
+
+	if err := p.requireClean(); err != nil {
+		return ReleaseResult{}, err
+	}
+
+	v, err := p.readVersion()
+	if err != nil {
+		return ReleaseResult{}, err
+	}
+	if err := p.applyBranchPolicy(v); err != nil {
+		return ReleaseResult{}, err
+	}
+
+	result := ReleaseResult{
+		Version: v.ToString(),
+		Tag:     fmt.Sprintf(constants.ReleaseTag, v.ToString()),
+	}
+
+	if !p.git.HasTag(result.Tag) {
+		if err := p.git.CreateTag(result.Tag, constants.TagMessage); err != nil {
+			return result, err
+		}
+		result.Created = true
+	}
+
+	if options.Push {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if err := p.git.PushTag(result.Tag); err != nil {
+			return result, err
+		}
+		result.Pushed = true
+	}
+
+	return result, nil
+}
+
+// plan reads the version and bumps it in memory.
+func (p *Project) plan(ctx context.Context, options BumpOptions) (*version.Version, BumpResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, BumpResult{}, err
+	}
+
+	v, err := p.readVersion()
+	if err != nil {
+		return nil, BumpResult{}, err
+	}
+	v.SetDeferWrite(true)
+
+	if err := p.applyBranchPolicy(v); err != nil {
+		return nil, BumpResult{}, err
+	}
+
+	result := BumpResult{PreviousVersion: v.ToString(), Segment: options.Segment}
+
+	if result.Segment == "" {
+		if err := p.repository(); err != nil {
+			return nil, BumpResult{}, err
+		}
+		result.Segment, result.Commits, err = p.detectSegment(v)
+		if err != nil {
+			return nil, BumpResult{}, err
+		}
+		result.Detected = true
+	} else if !slices.Contains(v.GetSegments(), result.Segment) {
+		result.Segment = v.LevelSegment(result.Segment)
+	}
+
+	if err := v.Bump(result.Segment); err != nil {
+		return nil, BumpResult{}, err
+	}
+
+	result.Version = v.ToString()
+	return v, result, nil
+}
+
+// detectSegment detects the segment of the scheme to bump from the commits
+// since the last version or release tag.
+func (p *Project) detectSegment(v *version.Version) (string, int, error) {
+	detection, err := analyzer.DetectPriority(p.git, v.ToString(), v.GetLastVersion(), p.logger)
+	if err != nil {
+		return "", 0, err
+	}
+	if detection.Priority == analyzer.PriorityNone {
+		return "", detection.Commits, NoChangesError(detection.Tag)
+	}
+	return v.LevelSegment(analyzer.Level(detection.Priority)), detection.Commits, nil
+}
+
+// readVersion reads the version files with the configured scheme.
+func (p *Project) readVersion() (*version.Version, error) {
+	scheme, err := p.newScheme()
+	if err != nil {
+		return nil, err
+	}
+
+	v := version.New()
+	v.SetLogger(p.logger)
+	v.SetFs(p.fs)
+	v.SetScheme(scheme)
+	v.SetFilePath(filepath.Join(p.dir, constants.ConfigFolderName))
+	v.SetFileName(constants.VersionFileName)
+	if err := v.ReadVersion(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// newScheme creates the configured scheme. The calver scheme takes its
+// dates from the clock of the project instead of the global clock.
+func (p *Project) newScheme() (version.Scheme, error) {
+	scheme := p.config.Scheme
+	if scheme.Name == version.SchemeCalVer {
+		return version.NewCalVer(scheme.Format, p.clock)
+	}
+	return version.NewScheme(scheme.Name, scheme.Format, scheme.Segments)
+}
+
+// applyBranchPolicy configures the version from the policy matching the
+// HEAD branch. Without configured policies every branch may be versioned.
+func (p *Project) applyBranchPolicy(v *version.Version) error {
+	policies := p.config.Branches
+	if len(policies) == 0 {
+		return nil
+	}
+
+	if err := p.repository(); err != nil {
+		return err
+	}
+
+	branch, err := p.git.GetBranchName()
+	if err != nil {
+		return err
+	}
+
+	match, err := policy.Match(policies, branch)
+	if err != nil {
+		return err
+	}
+	p.logger.Debug("branch policy matched", "branch", branch, "pattern", match.Pattern, "type", match.Type)
+
+	if !match.AllowsBump() {
+		return policy.BranchNotAllowedError(branch)
+	}
+
+	v.SetPreReleaseIdentifier(match.PreReleaseIdentifier())
+	v.SetBumpLimit(match.MaxBump)
+	if line := match.VersionLine(branch); line != "" {
+		return v.SetVersionLine(line)
+	}
+	return nil
+}
+
+// repository opens the git repository on first use.
+func (p *Project) repository() error {
+	if p.gitRead {
+		return nil
+	}
+	if err := p.git.ReadRepository(); err != nil {
+		return RepositoryError{p.dir, err}
+	}
+	p.gitRead = true
+	return nil
+}
+
+func (p *Project) requireClean() error {
+	if err := p.repository(); err != nil {
+		return err
+	}
+
+	clean, err := p.git.IsCleanRepo()
+	if err != nil {
+		return err
+	}
+	if !clean {
+		return DirtyRepositoryError(p.dir)
+	}
+	return nil
+}
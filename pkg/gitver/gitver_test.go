@@ -0,0 +1,437 @@
+package gitver
+
+import (
+	"context"
+	"errors"
+	"github.com/spf13/afero"
+	"gotver/internal/constants"
+	"gotver/internal/gittest"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testDir is the project directory in the in-memory filesystem.
+const testDir = "/project"
+
+// testConfig is the minimal valid project configuration.
+const testConfig = "schemaVersion: 1\n"
+
+// writeProject writes a project with the version, the last version and the
+// configuration given to fs.
+func writeProject(t *testing.T, fs afero.Fs, current, last, config string) {
+	t.Helper()
+
+	files := map[string]string{
+		constants.ConfigName + "." + constants.ConfigType: config,
+		constants.VersionFileName:                         current,
+		".lastversion":                                    last,
+	}
+	for name, content := range files {
+		if err := afero.WriteFile(fs, filepath.Join(testDir, constants.ConfigFolderName, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+}
+
+// testLogger discards all log records.
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// openProject opens a project in an in-memory filesystem with the version,
+// the last version and the configuration given. Its git repository is the
+// one of repo.
+func openProject(t *testing.T, repo *gittest.Repo, current, last, config string, options ...Option) (*Project, afero.Fs) {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	writeProject(t, fs, current, last, config)
+
+	p, err := Open(testDir, append([]Option{WithFs(fs), WithLogger(testLogger)}, options...)...)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := p.git.SetRepository(repo.Repository); err != nil {
+		t.Fatalf("set repository: %v", err)
+	}
+	return p, fs
+}
+
+// versionFiles returns the content of the version and the last version file.
+func versionFiles(t *testing.T, fs afero.Fs) (string, string) {
+	t.Helper()
+
+	var contents []string
+	for _, name := range []string{constants.VersionFileName, ".lastversion"} {
+		data, err := afero.ReadFile(fs, filepath.Join(testDir, constants.ConfigFolderName, name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		contents = append(contents, string(data))
+	}
+	return contents[0], contents[1]
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		config  string
+		want    string
+		wantErr error
+	}{
+		{"project directory", testDir, testConfig, testDir, nil},
+		{"subdirectory", testDir + "/src/pkg", testConfig, testDir, nil},
+		{"no project", "/other", testConfig, "", ErrNotFound},
+		{"newer schema", testDir, "schemaVersion: 99\n", "", ErrInvalidConfig},
+		{"invalid config", testDir, "schemaVersion: [1]\n", "", ErrInvalidConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			writeProject(t, fs, "1.0.0", "0.9.0", tt.config)
+
+			p, err := Open(tt.path, WithFs(fs), WithLogger(testLogger))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Open() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			if p.Dir() != tt.want {
+				t.Errorf("Dir() = %q, want %q", p.Dir(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag v1.0.0`)
+
+	tests := []struct {
+		current string
+		want    State
+	}{
+		{"1.0.0", State{Version: "1.0.0", LastVersion: "0.9.0", Scheme: "semver", Tagged: true}},
+		{"1.1.0-rc.1", State{Version: "1.1.0-rc.1", LastVersion: "0.9.0", PreRelease: "rc.1", Scheme: "semver"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current, func(t *testing.T) {
+			p, _ := openProject(t, repo, tt.current, "0.9.0", testConfig+"scheme:\n  name: semver\n")
+
+			state, err := p.Current(context.Background())
+			if err != nil {
+				t.Fatalf("Current() error = %v", err)
+			}
+			if state != tt.want {
+				t.Errorf("Current() = %+v, want %+v", state, tt.want)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name         string
+		commits      string
+		config       string
+		segment      string
+		want         string
+		wantSegment  string
+		wantDetected bool
+		wantErr      error
+	}{
+		{"feature", "commit feat: api", testConfig, "", "1.1.0", "minor", true, nil},
+		{"fix", "commit fix: bug", testConfig, "", "1.0.1", "patch", true, nil},
+		{"breaking change", "commit feat!: api", testConfig, "", "2.0.0", "major", true, nil},
+		{"no changes", "commit chore: deps", testConfig, "", "", "", false, ErrNoChange},
+		{"explicit segment", "commit chore: deps", testConfig, "major", "2.0.0", "major", false, nil},
+		{"unknown segment", "commit chore: deps", testConfig, "epoch", "", "", false, ErrInvalidInput},
+		{"scheme segment", "commit fix: bug", testConfig + "scheme:\n  name: fourpart\n", "", "1.0.1.0", "build", true, nil},
+		{"scheme level", "commit chore: deps", testConfig + "scheme:\n  name: fourpart\n", "patch", "1.0.1.0", "build", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := "1.0.0"
+			if tt.config != testConfig {
+				current = "1.0.0.0"
+			}
+			repo := gittest.New(t).Run("commit feat: initial").Tag("r" + current).Run(tt.commits)
+			p, fs := openProject(t, repo, current, "0.9.0", tt.config)
+
+			result, err := p.Next(context.Background(), BumpOptions{Segment: tt.segment})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Next() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Next() error = %v", err)
+			}
+
+			if result.Version != tt.want || result.Segment != tt.wantSegment || result.Detected != tt.wantDetected {
+				t.Errorf("Next() = %s (%s, detected %t), want %s (%s, detected %t)",
+					result.Version, result.Segment, result.Detected, tt.want, tt.wantSegment, tt.wantDetected)
+			}
+			if got, _ := versionFiles(t, fs); got != current {
+				t.Errorf("version file = %q, want %q unchanged", got, current)
+			}
+		})
+	}
+}
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		name    string
+		options BumpOptions
+	}{
+		{"write", BumpOptions{Segment: "minor"}},
+		{"commit", BumpOptions{Segment: "minor", Commit: true}},
+		{"push", BumpOptions{Segment: "minor", Push: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := gittest.New(t).Run(`
+				commit feat: initial
+				tag v1.0.0`)
+			remote := repo.AddRemote("origin")
+			repo.Push("origin")
+			p, fs := openProject(t, repo, "1.0.0", "0.9.0", testConfig)
+
+			previousHead := repo.Head()
+			result, err := p.Bump(context.Background(), tt.options)
+			if err != nil {
+				t.Fatalf("Bump() error = %v", err)
+			}
+
+			if current, last := versionFiles(t, fs); current != "1.1.0" || last != "1.0.0" {
+				t.Errorf("version files = %q, %q, want 1.1.0, 1.0.0", current, last)
+			}
+			if result.PreviousVersion != "1.0.0" || result.Version != "1.1.0" || len(result.Files) != 2 {
+				t.Errorf("Bump() = %+v, want 1.0.0 -> 1.1.0 with two files", result)
+			}
+
+			committed := tt.options.Commit || tt.options.Push
+			if result.Committed != committed || (repo.Head() != previousHead) != committed {
+				t.Errorf("committed = %t, want %t", result.Committed, committed)
+			}
+			if committed {
+				head, err := p.git.GetHeadCommit()
+				if err != nil {
+					t.Fatalf("GetHeadCommit() error = %v", err)
+				}
+				if want := "Bump Version [1.0.0] -> [1.1.0]"; head.Message != want {
+					t.Errorf("commit message = %q, want %q", head.Message, want)
+				}
+				if result.Tag != "v1.1.0" || repo.Resolve("v1.1.0") != head.Hash {
+					t.Errorf("tag %q does not point to the bump commit", result.Tag)
+				}
+			}
+
+			pushed := tt.options.Push
+			if result.Pushed != pushed || (remote.Branch(gittest.DefaultBranch) != previousHead) != pushed {
+				t.Errorf("pushed = %t, want %t", result.Pushed, pushed)
+			}
+		})
+	}
+}
+
+func TestBumpDirtyRepository(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		file notes.txt draft`)
+	p, fs := openProject(t, repo, "1.0.0", "0.9.0", testConfig)
+
+	_, err := p.Bump(context.Background(), BumpOptions{Segment: "patch", Commit: true})
+	if !errors.Is(err, ErrDirtyRepository) {
+		t.Fatalf("Bump() error = %v, want %v", err, ErrDirtyRepository)
+	}
+	if current, _ := versionFiles(t, fs); current != "1.0.0" {
+		t.Errorf("version file = %q, want 1.0.0", current)
+	}
+}
+
+func TestBumpRollback(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(repo *gittest.Repo)
+		want  error
+	}{
+		{
+			name:  "tag exists",
+			setup: func(repo *gittest.Repo) { repo.Tag("v1.0.1") },
+			want:  ErrGit,
+		},
+		{
+			name:  "push fails",
+			setup: func(repo *gittest.Repo) { repo.AddRemote("origin").Disconnect() },
+			want:  ErrRemote,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := gittest.New(t).Run(`
+				commit feat: initial
+				tag v1.0.0
+				commit fix: bug`)
+			tt.setup(repo)
+			p, fs := openProject(t, repo, "1.0.0", "0.9.0", testConfig)
+
+			head := repo.Head()
+			_, err := p.Bump(context.Background(), BumpOptions{Segment: "patch", Push: true})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Bump() error = %v, want %v", err, tt.want)
+			}
+
+			if got := repo.Head(); got != head {
+				t.Errorf("HEAD = %s, want %s", got, head)
+			}
+			if current, last := versionFiles(t, fs); current != "1.0.0" || last != "0.9.0" {
+				t.Errorf("version files = %q, %q, want 1.0.0, 0.9.0", current, last)
+			}
+			if tt.want == ErrRemote && p.git.HasTag("v1.0.1") {
+				t.Error("tag v1.0.1 was not deleted")
+			}
+		})
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	repo := gittest.New(t).Run("commit feat: initial")
+	clock := func() time.Time { return time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC) }
+	p, _ := openProject(t, repo, "2025.02.3", "2025.02.2", testConfig+"scheme:\n  name: calver\n", WithClock(clock))
+
+	result, err := p.Next(context.Background(), BumpOptions{Segment: "MICRO"})
+	if err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+	if result.Version != "2025.03.0" {
+		t.Errorf("Next() = %s, want 2025.03.0", result.Version)
+	}
+}
+
+func TestRelease(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag v1.0.0`)
+	remote := repo.AddRemote("origin")
+	repo.Push("origin")
+	p, _ := openProject(t, repo, "1.0.0", "0.9.0", testConfig)
+
+	result, err := p.Release(context.Background(), ReleaseOptions{Push: true})
+	if err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	want := ReleaseResult{Version: "1.0.0", Tag: "r1.0.0", Created: true, Pushed: true}
+	if result != want {
+		t.Errorf("Release() = %+v, want %+v", result, want)
+	}
+	if repo.Resolve("r1.0.0") != repo.Head() {
+		t.Error("release tag does not point to HEAD")
+	}
+	if _, err := remote.Repository.Tag("r1.0.0"); err != nil {
+		t.Errorf("release tag was not pushed: %v", err)
+	}
+
+	result, err = p.Release(context.Background(), ReleaseOptions{})
+	if err != nil {
+		t.Fatalf("Release() again error = %v", err)
+	}
+	if result.Created {
+		t.Error("Release() again created the existing tag")
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag v1.0.0
+		commit feat: api
+		commit fix: bug
+		tag v1.1.0
+		tag r1.1.0
+		commit fix: crash
+		tag v1.1.1
+		commit fix: leak`)
+
+	tests := []struct {
+		name            string
+		current         string
+		options         ChangelogOptions
+		wantTag         string
+		wantPreviousTag string
+		wantSections    map[string]int
+		wantErr         error
+	}{
+		{"current version", "1.1.0", ChangelogOptions{}, "v1.1.0", "v1.0.0", map[string]int{"feat": 1, "fix": 1}, nil},
+		{"untagged current version", "1.1.2", ChangelogOptions{}, "", "v1.1.1", map[string]int{"fix": 1}, nil},
+		{"version", "1.1.1", ChangelogOptions{Version: "1.0.0"}, "v1.0.0", "", map[string]int{"feat": 1}, nil},
+		{"range", "1.1.1", ChangelogOptions{Range: "v1.0.0..v1.1.0"}, "v1.1.0", "v1.0.0", map[string]int{"feat": 1, "fix": 1}, nil},
+		{"missing version tag", "1.1.1", ChangelogOptions{Version: "2.0.0"}, "", "", nil, ErrGit},
+		{"missing range tag", "1.1.1", ChangelogOptions{Range: "v1.0.0..v2.0.0"}, "", "", nil, ErrGit},
+		{"range of release tags", "1.1.1", ChangelogOptions{Range: "r1.1.0..v1.1.1"}, "v1.1.1", "r1.1.0", map[string]int{"fix": 1}, nil},
+		{"invalid range", "1.1.1", ChangelogOptions{Range: "v1.0.0"}, "", "", nil, ErrInvalidInput},
+		{"version and range", "1.1.1", ChangelogOptions{Version: "1.0.0", Range: "v1.0.0..v1.1.0"}, "", "", nil, ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := openProject(t, repo, tt.current, "1.0.0", testConfig)
+
+			c, err := p.Changelog(context.Background(), tt.options)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Changelog() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Changelog() error = %v", err)
+			}
+
+			if c.Tag != tt.wantTag || c.PreviousTag != tt.wantPreviousTag {
+				t.Errorf("Changelog() tags = %q..%q, want %q..%q", c.PreviousTag, c.Tag, tt.wantPreviousTag, tt.wantTag)
+			}
+			sections := make(map[string]int)
+			for _, section := range c.Sections {
+				sections[section.Type] = len(section.Entries)
+			}
+			if !reflect.DeepEqual(sections, tt.wantSections) {
+				t.Errorf("Changelog() sections = %v, want %v", sections, tt.wantSections)
+			}
+		})
+	}
+}
+
+func TestChangelogReleaseTags(t *testing.T) {
+	repo := gittest.New(t).Run(`
+		commit feat: initial
+		tag r1.0.0
+		commit feat: api
+		commit fix: bug`)
+	p, _ := openProject(t, repo, "1.1.0", "1.0.0", testConfig)
+
+	c, err := p.Changelog(context.Background(), ChangelogOptions{})
+	if err != nil {
+		t.Fatalf("Changelog() error = %v", err)
+	}
+	if c.PreviousTag != "r1.0.0" || c.PreviousVersion != "1.0.0" {
+		t.Errorf("Changelog() previous = %q (%s), want r1.0.0 (1.0.0)", c.PreviousTag, c.PreviousVersion)
+	}
+	if len(c.Sections) != 2 {
+		t.Errorf("Changelog() sections = %+v, want the feature and the fix since r1.0.0", c.Sections)
+	}
+}
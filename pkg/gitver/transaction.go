@@ -0,0 +1,80 @@
+package gitver
+
+import (
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/afero"
+	"os"
+)
+
+// transaction records what a bump changed, so it can be undone when a git
+// operation fails.
+type transaction struct {
+	project *Project
+	files   map[string][]byte
+	head    plumbing.Hash
+	tags    []string
+}
+
+func (p *Project) newTransaction() *transaction {
+	return &transaction{project: p, files: make(map[string][]byte)}
+}
+
+// snapshot remembers the content of files before they are written. Files
+// that do not exist yet are removed on rollback.
+func (t *transaction) snapshot(files ...string) {
+	for _, file := range files {
+		if _, ok := t.files[file]; ok {
+			continue
+		}
+
+		data, err := afero.ReadFile(t.project.fs, file)
+		if err != nil {
+			data = nil
+		}
+		t.files[file] = data
+	}
+}
+
+// recordHead remembers the commit HEAD points to before the bump commits.
+func (t *transaction) recordHead() error {
+	commit, err := t.project.git.GetHeadCommit()
+	if err != nil {
+		return err
+	}
+	t.head = commit.Hash
+	return nil
+}
+
+// fail undoes the bump and returns the error that caused it.
+func (t *transaction) fail(err error) error {
+	t.rollback()
+	return err
+}
+
+func (t *transaction) rollback() {
+	p := t.project
+	p.logger.Info("roll back bump")
+	for _, tag := range t.tags {
+		if err := p.git.DeleteTag(tag); err != nil {
+			p.logger.Error("tag could not be deleted", "tag", tag, "error", err)
+		}
+	}
+
+	if !t.head.IsZero() {
+		if err := p.git.ResetHard(t.head); err != nil {
+			p.logger.Error("reset failed", "commit", t.head.String(), "error", err)
+		}
+	}
+
+	for file, data := range t.files {
+		var err error
+		if data == nil {
+			err = p.fs.Remove(file)
+		} else {
+			err = afero.WriteFile(p.fs, file, data, os.ModePerm)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			p.logger.Error("file could not be restored", "file", file, "error", err)
+		}
+	}
+}